@@ -0,0 +1,25 @@
+package parser
+
+import (
+	"errors"
+	"net/netip"
+)
+
+var (
+	ErrInvalidAddress = errors.New("invalid address")
+)
+
+// parseAddrs parses the address tokens of SCTP proc contents.
+// The kernel prints IPv6 addresses in the full form (e.g. `fe80:0000:0000:0000:0000:0000:0000:0001`); that is also accepted.
+// An IPv6 address may have a zone (e.g. `fe80::1%eth0`).
+func parseAddrs(addrs []string) ([]netip.Addr, error) {
+	ips := make([]netip.Addr, len(addrs))
+	for i, addr := range addrs {
+		ip, err := netip.ParseAddr(addr)
+		if err != nil {
+			return nil, err
+		}
+		ips[i] = ip
+	}
+	return ips, nil
+}
@@ -4,7 +4,7 @@ import (
 	"bufio"
 	"errors"
 	"fmt"
-	"strconv"
+	"net/netip"
 	"strings"
 )
 
@@ -15,38 +15,66 @@ var (
 
 // Assoc represents the structure of SCTP assoc.
 type Assoc struct {
-	Assoc   uint64
-	Sock    uint64
-	Sty     int64
-	Sst     int64
-	St      int64
-	Hbkt    int64
-	AssocId int64
-	TxQueue int64
-	RxQueue int64
-	Uid     uint64
-	Inode   uint64
-	LPort   int64
-	RPort   int64
-	LAddrs  []string
-	RAddrs  []string
-	Hbint   uint64
-	Ins     int64
-	Outs    int64
-	Maxrt   int64
-	T1x     int64
-	T2x     int64
-	Rtxc    int64
-	Wmema   int64
-	Wmemq   int64
-	Sndbuf  int64
-	Rcvbuf  int64
+	Assoc   uint64      `json:"assoc" yaml:"assoc"`
+	Sock    uint64      `json:"sock" yaml:"sock"`
+	Sty     SocketType  `json:"sty" yaml:"sty"`
+	Sst     SocketState `json:"sst" yaml:"sst"`
+	St      AssocState  `json:"st" yaml:"st"`
+	Hbkt    int64       `json:"hbkt" yaml:"hbkt"`
+	AssocId int64       `json:"assoc_id" yaml:"assoc_id"`
+	TxQueue int64       `json:"tx_queue" yaml:"tx_queue"`
+	RxQueue int64       `json:"rx_queue" yaml:"rx_queue"`
+	Uid     uint64      `json:"uid" yaml:"uid"`
+	Inode   uint64      `json:"inode" yaml:"inode"`
+	LPort   int64       `json:"lport" yaml:"lport"`
+	RPort   int64       `json:"rport" yaml:"rport"`
+	LAddrs  []string    `json:"laddrs" yaml:"laddrs"`
+	RAddrs  []string    `json:"raddrs" yaml:"raddrs"`
+	Hbint   uint64      `json:"hbint" yaml:"hbint"` // in jiffies, as the kernel prints it; see JiffiesToDuration
+	Ins     int64       `json:"ins" yaml:"ins"`
+	Outs    int64       `json:"outs" yaml:"outs"`
+	Maxrt   int64       `json:"maxrt" yaml:"maxrt"`
+	T1x     int64       `json:"t1x" yaml:"t1x"`
+	T2x     int64       `json:"t2x" yaml:"t2x"`
+	Rtxc    int64       `json:"rtxc" yaml:"rtxc"`
+	Wmema   int64       `json:"wmema" yaml:"wmema"`
+	Wmemq   int64       `json:"wmemq" yaml:"wmemq"`
+	Sndbuf  int64       `json:"sndbuf" yaml:"sndbuf"`
+	Rcvbuf  int64       `json:"rcvbuf" yaml:"rcvbuf"`
+
+	// LAddrIPs and RAddrIPs are the parsed forms of LAddrs and RAddrs. They are in the same order as LAddrs and RAddrs.
+	LAddrIPs []netip.Addr `json:"-" yaml:"-"`
+	RAddrIPs []netip.Addr `json:"-" yaml:"-"`
+
+	// PrimaryLAddr is the local address of the primary path, which is marked with `*` by the kernel. It is empty if there is no marked address.
+	PrimaryLAddr string `json:"primary_laddr" yaml:"primary_laddr"`
+	// PrimaryRAddr is the remote address of the primary path, which is marked with `*` by the kernel. It is empty if there is no marked address.
+	PrimaryRAddr string `json:"primary_raddr" yaml:"primary_raddr"`
+
+	// Owners are the processes that have the socket open. This is set by SocketOwners.AnnotateAssocs.
+	Owners []*Process `json:"owners,omitempty" yaml:"owners,omitempty"`
+	// UserName is the name of the user of Uid. This is set by UserResolver.AnnotateAssocs.
+	UserName string `json:"user_name,omitempty" yaml:"user_name,omitempty"`
+	// Container is the container whose network namespace the association is in. This is set by Container.AnnotateAssocs.
+	Container *Container `json:"container,omitempty" yaml:"container,omitempty"`
+
+	// Absent is the set of the fields that the kernel didn't emit; e.g. older kernels don't have wmema, wmemq, sndbuf and rcvbuf.
+	// These fields are left as zero values; the encodings tell them by `absent` in JSON and YAML, and by empty cells in CSV.
+	Absent AssocField `json:"absent,omitempty" yaml:"absent,omitempty"`
+}
+
+// Has returns whether the field has been emitted by the kernel.
+func (a *Assoc) Has(field AssocField) bool {
+	return a.Absent&field == 0
 }
 
 // ParseAssocs parses SCTP assocs contents; for example the contents of `/proc/net/sctp/assocs` file.
 //
+// The columns are located according to the header line, so the order of the columns may differ from the example below.
+// The columns that older kernels don't emit (e.g. wmema, wmemq, sndbuf and rcvbuf) are marked in Assoc.Absent.
+//
 // - input: the contents of SCTP assocs
-// - noHeader: this has to be true if the input doesn't have a header line (default: `false`)
+// - noHeader: this has to be true if the input doesn't have a header line (default: `false`). In that case, the layout is detected by the number of the columns after the remote addresses.
 //
 // example input:
 // ```
@@ -55,183 +83,172 @@ type Assoc struct {
 //       0        0 2   1   3  0      59        0        0       0 189472 54321 12345  127.0.0.2 <-> *127.0.0.1     30000 65535 65535   10    0    0        0        1        0   212992   212992
 // ```
 func ParseAssocs(input *bufio.Scanner, noHeader ...bool) ([]*Assoc, error) {
-	// implementation memo:
-	// https://github.com/torvalds/linux/blob/dcc0b49040c70ad827a7f3d58a21b01fdb14e749/net/sctp/proc.c#L243
+	scanner := NewAssocScanner(input, noHeader...)
 
-	lineNum := 1
-	if len(noHeader) <= 0 || !noHeader[0] {
-		input.Scan() // skip a header line
-		lineNum++
+	assocs := make([]*Assoc, 0)
+	for scanner.Next() {
+		assocs = append(assocs, scanner.Record())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
 	}
 
-	assocs := make([]*Assoc, 0)
+	return assocs, nil
+}
 
-	for input.Scan() {
-		if input.Text() == "" {
-			lineNum++
-			continue
-		}
+// AssocScanner reads SCTP assocs contents one record at a time.
+// This is useful to process a large number of associations without holding all of them in memory.
+type AssocScanner struct {
+	lineScanner
+	layout *columnLayout
+	absent AssocField
+	record *Assoc
+}
 
-		leaves := spacesRe.Split(strings.TrimSpace(input.Text()), -1)
-		leavesLen := len(leaves)
-		if leavesLen < 27 {
-			return nil, fmt.Errorf("at line #%d: %w", lineNum, ErrInsufficientNumberOfAssocItems)
-		}
+// NewAssocScanner returns a new AssocScanner that reads SCTP assocs contents from the input.
+//
+// - input: the contents of SCTP assocs
+// - noHeader: this has to be true if the input doesn't have a header line (default: `false`)
+func NewAssocScanner(input *bufio.Scanner, noHeader ...bool) *AssocScanner {
+	return &AssocScanner{
+		lineScanner: newLineScanner(input, noHeader),
+	}
+}
 
-		assoc, err := strconv.ParseUint(leaves[0], 16, 64)
-		if err != nil {
-			return nil, fmt.Errorf("ASSOC at line #%d: %w", lineNum, ErrInvalidAssocsFormat)
-		}
-		sock, err := strconv.ParseUint(leaves[1], 16, 64)
-		if err != nil {
-			return nil, fmt.Errorf("SOCK at line #%d: %w", lineNum, ErrInvalidAssocsFormat)
-		}
-		sty, err := strconv.ParseInt(leaves[2], 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("STY at line #%d: %w", lineNum, ErrInvalidAssocsFormat)
-		}
-		sst, err := strconv.ParseInt(leaves[3], 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("SST at line #%d: %w", lineNum, ErrInvalidAssocsFormat)
-		}
-		st, err := strconv.ParseInt(leaves[4], 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("ST at line #%d: %w", lineNum, ErrInvalidAssocsFormat)
-		}
-		hbkt, err := strconv.ParseInt(leaves[5], 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("HBKT at line #%d: %w", lineNum, ErrInvalidAssocsFormat)
-		}
-		assocId, err := strconv.ParseInt(leaves[6], 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("ASSOC-ID at line #%d: %w", lineNum, ErrInvalidAssocsFormat)
-		}
-		txQueue, err := strconv.ParseInt(leaves[7], 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("TX_QUEUE at line #%d: %w", lineNum, ErrInvalidAssocsFormat)
-		}
-		rxQueue, err := strconv.ParseInt(leaves[8], 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("RX_QUEUE at line #%d: %w", lineNum, ErrInvalidAssocsFormat)
-		}
-		uid, err := strconv.ParseUint(leaves[9], 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("UID at line #%d: %w", lineNum, ErrInvalidAssocsFormat)
-		}
-		inode, err := strconv.ParseUint(leaves[10], 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("INODE at line #%d: %w", lineNum, ErrInvalidAssocsFormat)
-		}
-		lport, err := strconv.ParseInt(leaves[11], 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("LPORT at line #%d: %w", lineNum, ErrInvalidAssocsFormat)
-		}
-		rport, err := strconv.ParseInt(leaves[12], 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("RPORT at line #%d: %w", lineNum, ErrInvalidAssocsFormat)
-		}
+// Next advances the scanner to the next assoc record, which will then be available through the Record method.
+// It returns false when the scan stops, either by reaching the end of the input or an error.
+func (s *AssocScanner) Next() bool {
+	s.record = nil
 
-		cur := 13
-		laddrs := make([]string, 0, 1)
-		for {
-			if cur >= leavesLen {
-				return nil, fmt.Errorf("there is no separater ('<->') for laddr and raddr: %w", ErrInvalidAssocsFormat)
-			}
+	line, lineNum, ok := s.nextLine()
+	if !ok {
+		return false
+	}
 
-			leaf := leaves[cur]
-			cur++
-			if leaf == "<->" {
-				break
+	if s.layout == nil {
+		if s.noHeader {
+			s.layout = detectAssocsLayout(line)
+		} else {
+			layout, ok := resolveAssocsLayout(s.header)
+			if !ok {
+				s.err = fmt.Errorf("header at line #1: %w", ErrInvalidAssocsFormat)
+				return false
 			}
-
-			laddrs = append(laddrs, leaf)
+			s.layout = layout
 		}
+		s.absent = absentAssocFields(s.layout)
+	}
 
-		endCursorForRaddrs := leavesLen - 11
-		raddrs := make([]string, 0, 1)
-		for {
-			if cur >= endCursorForRaddrs {
-				break
-			}
-			raddrs = append(raddrs, strings.Trim(leaves[cur], "*"))
-			cur++
-		}
+	assoc, err := parseAssocLine(line, lineNum, s.layout)
+	if err != nil {
+		s.err = err
+		return false
+	}
+	assoc.Absent = s.absent
+	s.record = assoc
 
-		hbint, err := strconv.ParseUint(leaves[leavesLen-11], 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("HBINT at line #%d: %w", lineNum, ErrInvalidAssocsFormat)
-		}
-		ins, err := strconv.ParseInt(leaves[leavesLen-10], 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("INS at line #%d: %w", lineNum, ErrInvalidAssocsFormat)
-		}
-		outs, err := strconv.ParseInt(leaves[leavesLen-9], 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("OUTS at line #%d: %w", lineNum, ErrInvalidAssocsFormat)
-		}
-		maxrt, err := strconv.ParseInt(leaves[leavesLen-8], 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("MAXRT at line #%d: %w", lineNum, ErrInvalidAssocsFormat)
-		}
-		t1x, err := strconv.ParseInt(leaves[leavesLen-7], 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("T1X at line #%d: %w", lineNum, ErrInvalidAssocsFormat)
+	return true
+}
+
+// Record returns the most recent assoc record generated by a call to Next.
+func (s *AssocScanner) Record() *Assoc {
+	return s.record
+}
+
+func parseAssocLine(line string, lineNum int, layout *columnLayout) (*Assoc, error) {
+	// implementation memo:
+	// https://github.com/torvalds/linux/blob/dcc0b49040c70ad827a7f3d58a21b01fdb14e749/net/sctp/proc.c#L243
+
+	leaves := spacesRe.Split(strings.TrimSpace(line), -1)
+	leavesLen := len(leaves)
+	leadingLen := len(layout.leading)
+	trailingLen := len(layout.trailing)
+	if leavesLen < leadingLen+trailingLen+3 { // +3: at least one laddr, the separator and at least one raddr
+		return nil, fmt.Errorf("at line #%d: %w", lineNum, ErrInsufficientNumberOfAssocItems)
+	}
+
+	assoc := &Assoc{}
+	for i, name := range layout.leading {
+		if err := setAssocColumn(assoc, name, leaves[i], lineNum); err != nil {
+			return nil, err
 		}
-		t2x, err := strconv.ParseInt(leaves[leavesLen-6], 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("T2X at line #%d: %w", lineNum, ErrInvalidAssocsFormat)
+	}
+
+	cur := leadingLen
+	laddrs := make([]string, 0, 1)
+	for {
+		if cur >= leavesLen {
+			return nil, fmt.Errorf("there is no separater ('<->') for laddr and raddr: %w", ErrInvalidAssocsFormat)
 		}
-		rtxc, err := strconv.ParseInt(leaves[leavesLen-5], 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("RTXC at line #%d: %w", lineNum, ErrInvalidAssocsFormat)
+
+		leaf := leaves[cur]
+		cur++
+		if leaf == "<->" {
+			break
 		}
-		wmema, err := strconv.ParseInt(leaves[leavesLen-4], 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("wmema at line #%d: %w", lineNum, ErrInvalidAssocsFormat)
+
+		if addr, ok := trimPrimaryMarker(leaf); ok {
+			assoc.PrimaryLAddr = addr
+			leaf = addr
 		}
-		wmemq, err := strconv.ParseInt(leaves[leavesLen-3], 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("wmemq at line #%d: %w", lineNum, ErrInvalidAssocsFormat)
+		laddrs = append(laddrs, leaf)
+	}
+
+	endCursorForRaddrs := leavesLen - trailingLen
+	raddrs := make([]string, 0, 1)
+	for {
+		if cur >= endCursorForRaddrs {
+			break
 		}
-		sndbuf, err := strconv.ParseInt(leaves[leavesLen-2], 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("sndbuf at line #%d: %w", lineNum, ErrInvalidAssocsFormat)
+
+		leaf := leaves[cur]
+		cur++
+		if addr, ok := trimPrimaryMarker(leaf); ok {
+			assoc.PrimaryRAddr = addr
+			leaf = addr
 		}
-		rcvbuf, err := strconv.ParseInt(leaves[leavesLen-1], 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("rcvbuf at line #%d: %w", lineNum, ErrInvalidAssocsFormat)
+		raddrs = append(raddrs, leaf)
+	}
+
+	for i, name := range layout.trailing {
+		if err := setAssocColumn(assoc, name, leaves[endCursorForRaddrs+i], lineNum); err != nil {
+			return nil, err
 		}
+	}
+
+	laddrIPs, err := parseAddrs(laddrs)
+	if err != nil {
+		return nil, fmt.Errorf("LADDRS at line #%d: %s: %w", lineNum, err, ErrInvalidAddress)
+	}
+	raddrIPs, err := parseAddrs(raddrs)
+	if err != nil {
+		return nil, fmt.Errorf("RADDRS at line #%d: %s: %w", lineNum, err, ErrInvalidAddress)
+	}
+
+	assoc.LAddrs = laddrs
+	assoc.RAddrs = raddrs
+	assoc.LAddrIPs = laddrIPs
+	assoc.RAddrIPs = raddrIPs
+
+	return assoc, nil
+}
 
-		lineNum++
-		assocs = append(assocs, &Assoc{
-			Assoc:   assoc,
-			Sock:    sock,
-			Sty:     sty,
-			Sst:     sst,
-			St:      st,
-			Hbkt:    hbkt,
-			AssocId: assocId,
-			TxQueue: txQueue,
-			RxQueue: rxQueue,
-			Uid:     uid,
-			Inode:   inode,
-			LPort:   lport,
-			RPort:   rport,
-			LAddrs:  laddrs,
-			RAddrs:  raddrs,
-			Hbint:   hbint,
-			Ins:     ins,
-			Outs:    outs,
-			Maxrt:   maxrt,
-			T1x:     t1x,
-			T2x:     t2x,
-			Rtxc:    rtxc,
-			Wmema:   wmema,
-			Wmemq:   wmemq,
-			Sndbuf:  sndbuf,
-			Rcvbuf:  rcvbuf,
-		})
+func setAssocColumn(assoc *Assoc, name string, value string, lineNum int) error {
+	column, ok := assocColumns[name]
+	if !ok {
+		return nil // unknown column
 	}
+	if err := column.set(assoc, value); err != nil {
+		return fmt.Errorf("%s at line #%d: %w", name, lineNum, ErrInvalidAssocsFormat)
+	}
+	return nil
+}
 
-	return assocs, nil
+// trimPrimaryMarker removes the primary path marker (`*`) from the address.
+// It returns true as the second value if the address has the marker.
+func trimPrimaryMarker(addr string) (string, bool) {
+	if !strings.HasPrefix(addr, "*") {
+		return addr, false
+	}
+	return addr[1:], true
 }
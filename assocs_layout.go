@@ -0,0 +1,221 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrUnknownAssocField = errors.New("unknown assoc field")
+)
+
+// AssocField identifies a column of SCTP assocs.
+// AssocField values can be combined with bitwise OR to represent a set of columns.
+type AssocField uint32
+
+const (
+	AssocFieldAssoc AssocField = 1 << iota
+	AssocFieldSock
+	AssocFieldSty
+	AssocFieldSst
+	AssocFieldSt
+	AssocFieldHbkt
+	AssocFieldAssocId
+	AssocFieldTxQueue
+	AssocFieldRxQueue
+	AssocFieldUid
+	AssocFieldInode
+	AssocFieldLPort
+	AssocFieldRPort
+	AssocFieldHbint
+	AssocFieldIns
+	AssocFieldOuts
+	AssocFieldMaxrt
+	AssocFieldT1x
+	AssocFieldT2x
+	AssocFieldRtxc
+	AssocFieldWmema
+	AssocFieldWmemq
+	AssocFieldSndbuf
+	AssocFieldRcvbuf
+)
+
+// assocFieldNames are the names of the fields in the order of the bits; they are the same as the JSON field names.
+var assocFieldNames = []string{
+	"assoc", "sock", "sty", "sst", "st", "hbkt", "assoc_id", "tx_queue", "rx_queue", "uid", "inode", "lport", "rport",
+	"hbint", "ins", "outs", "maxrt", "t1x", "t2x", "rtxc", "wmema", "wmemq", "sndbuf", "rcvbuf",
+}
+
+// MarshalText encodes the set of the fields as their names separated by spaces; e.g. `wmema wmemq sndbuf rcvbuf`.
+func (f AssocField) MarshalText() ([]byte, error) {
+	names := make([]string, 0, len(assocFieldNames))
+	for i, name := range assocFieldNames {
+		if f&(1<<i) != 0 {
+			names = append(names, name)
+		}
+	}
+	return []byte(strings.Join(names, " ")), nil
+}
+
+// UnmarshalText decodes the set of the fields from their names separated by spaces.
+func (f *AssocField) UnmarshalText(text []byte) error {
+	var fields AssocField
+	for _, name := range strings.Fields(string(text)) {
+		found := false
+		for i, fieldName := range assocFieldNames {
+			if fieldName == name {
+				fields |= 1 << i
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%q: %w", name, ErrUnknownAssocField)
+		}
+	}
+	*f = fields
+	return nil
+}
+
+// assocsHeaders are the header lines of the assocs layouts that the kernel has emitted, from the newest to the oldest.
+// The kernel prints them with a leading space.
+//
+// - the current layout
+// - the layout without the memory columns (wmema, wmemq, sndbuf and rcvbuf)
+// - the layout without any columns after the address lists
+var assocsHeaders = []string{
+	"ASSOC     SOCK   STY SST ST HBKT ASSOC-ID TX_QUEUE RX_QUEUE UID INODE LPORT RPORT LADDRS <-> RADDRS HBINT INS OUTS MAXRT T1X T2X RTXC wmema wmemq sndbuf rcvbuf",
+	"ASSOC     SOCK   STY SST ST HBKT ASSOC-ID TX_QUEUE RX_QUEUE UID INODE LPORT RPORT LADDRS <-> RADDRS HBINT INS OUTS MAXRT T1X T2X RTXC",
+	"ASSOC     SOCK   STY SST ST HBKT ASSOC-ID TX_QUEUE RX_QUEUE UID INODE LPORT RPORT LADDRS <-> RADDRS",
+}
+
+// assocsLayouts is the registry of the assocs layouts; each of them corresponds to assocsHeaders.
+var assocsLayouts = func() []*columnLayout {
+	layouts := make([]*columnLayout, len(assocsHeaders))
+	for i, header := range assocsHeaders {
+		layouts[i] = mustParseColumnLayout(header, assocsAddrColumns)
+	}
+	return layouts
+}()
+
+var (
+	assocsAddrColumns   = []string{"LADDRS", "<->", "RADDRS"}
+	defaultAssocsLayout = assocsLayouts[0]
+)
+
+type assocColumn struct {
+	field AssocField
+	set   func(a *Assoc, v string) error
+}
+
+// assocColumns maps the header names of assocs to the fields and the functions that set the column value to an Assoc.
+// Columns that are not in this map are ignored.
+var assocColumns = map[string]assocColumn{
+	"ASSOC":    {AssocFieldAssoc, func(a *Assoc, v string) (err error) { a.Assoc, err = strconv.ParseUint(v, 16, 64); return }},
+	"SOCK":     {AssocFieldSock, func(a *Assoc, v string) (err error) { a.Sock, err = strconv.ParseUint(v, 16, 64); return }},
+	"STY":      {AssocFieldSty, func(a *Assoc, v string) (err error) { a.Sty, err = parseIntColumn[SocketType](v); return }},
+	"SST":      {AssocFieldSst, func(a *Assoc, v string) (err error) { a.Sst, err = parseIntColumn[SocketState](v); return }},
+	"ST":       {AssocFieldSt, func(a *Assoc, v string) (err error) { a.St, err = parseIntColumn[AssocState](v); return }},
+	"HBKT":     {AssocFieldHbkt, func(a *Assoc, v string) (err error) { a.Hbkt, err = strconv.ParseInt(v, 10, 64); return }},
+	"ASSOC-ID": {AssocFieldAssocId, func(a *Assoc, v string) (err error) { a.AssocId, err = strconv.ParseInt(v, 10, 64); return }},
+	"TX_QUEUE": {AssocFieldTxQueue, func(a *Assoc, v string) (err error) { a.TxQueue, err = strconv.ParseInt(v, 10, 64); return }},
+	"RX_QUEUE": {AssocFieldRxQueue, func(a *Assoc, v string) (err error) { a.RxQueue, err = strconv.ParseInt(v, 10, 64); return }},
+	"UID":      {AssocFieldUid, func(a *Assoc, v string) (err error) { a.Uid, err = strconv.ParseUint(v, 10, 64); return }},
+	"INODE":    {AssocFieldInode, func(a *Assoc, v string) (err error) { a.Inode, err = strconv.ParseUint(v, 10, 64); return }},
+	"LPORT":    {AssocFieldLPort, func(a *Assoc, v string) (err error) { a.LPort, err = strconv.ParseInt(v, 10, 64); return }},
+	"RPORT":    {AssocFieldRPort, func(a *Assoc, v string) (err error) { a.RPort, err = strconv.ParseInt(v, 10, 64); return }},
+	"HBINT":    {AssocFieldHbint, func(a *Assoc, v string) (err error) { a.Hbint, err = strconv.ParseUint(v, 10, 64); return }},
+	"INS":      {AssocFieldIns, func(a *Assoc, v string) (err error) { a.Ins, err = strconv.ParseInt(v, 10, 64); return }},
+	"OUTS":     {AssocFieldOuts, func(a *Assoc, v string) (err error) { a.Outs, err = strconv.ParseInt(v, 10, 64); return }},
+	"MAXRT":    {AssocFieldMaxrt, func(a *Assoc, v string) (err error) { a.Maxrt, err = strconv.ParseInt(v, 10, 64); return }},
+	"T1X":      {AssocFieldT1x, func(a *Assoc, v string) (err error) { a.T1x, err = strconv.ParseInt(v, 10, 64); return }},
+	"T2X":      {AssocFieldT2x, func(a *Assoc, v string) (err error) { a.T2x, err = strconv.ParseInt(v, 10, 64); return }},
+	"RTXC":     {AssocFieldRtxc, func(a *Assoc, v string) (err error) { a.Rtxc, err = strconv.ParseInt(v, 10, 64); return }},
+	"wmema":    {AssocFieldWmema, func(a *Assoc, v string) (err error) { a.Wmema, err = strconv.ParseInt(v, 10, 64); return }},
+	"wmemq":    {AssocFieldWmemq, func(a *Assoc, v string) (err error) { a.Wmemq, err = strconv.ParseInt(v, 10, 64); return }},
+	"sndbuf":   {AssocFieldSndbuf, func(a *Assoc, v string) (err error) { a.Sndbuf, err = strconv.ParseInt(v, 10, 64); return }},
+	"rcvbuf":   {AssocFieldRcvbuf, func(a *Assoc, v string) (err error) { a.Rcvbuf, err = strconv.ParseInt(v, 10, 64); return }},
+}
+
+// allAssocFields is the set of all the fields that assocColumns can set.
+var allAssocFields = func() AssocField {
+	var fields AssocField
+	for _, column := range assocColumns {
+		fields |= column.field
+	}
+	return fields
+}()
+
+// resolveAssocsLayout returns the layout that is described by the header.
+// If the header is identical to one of the registered layouts, that one is returned.
+func resolveAssocsLayout(header string) (*columnLayout, bool) {
+	layout, ok := parseColumnLayout(header, assocsAddrColumns)
+	if !ok {
+		return nil, false
+	}
+	for _, known := range assocsLayouts {
+		if equalStrings(known.leading, layout.leading) && equalStrings(known.trailing, layout.trailing) {
+			return known, true
+		}
+	}
+	return layout, true
+}
+
+// detectAssocsLayout guesses the layout of a headerless assocs line by the number of the numeric fields
+// that follow the remote addresses. It falls back to the newest layout if no registered layout matches.
+func detectAssocsLayout(line string) *columnLayout {
+	leaves := spacesRe.Split(strings.TrimSpace(line), -1)
+
+	sep := -1
+	for i, leaf := range leaves {
+		if leaf == "<->" {
+			sep = i
+			break
+		}
+	}
+	if sep < 0 {
+		return defaultAssocsLayout
+	}
+
+	numTrailing := 0
+	for i := len(leaves) - 1; i > sep+1; i-- { // leaves[sep+1] must be a remote address
+		if !isDigits(leaves[i]) {
+			break
+		}
+		numTrailing++
+	}
+
+	for _, layout := range assocsLayouts {
+		if len(layout.trailing) == numTrailing {
+			return layout
+		}
+	}
+	return defaultAssocsLayout
+}
+
+// absentAssocFields returns the set of fields that don't appear in the layout.
+func absentAssocFields(layout *columnLayout) AssocField {
+	present := AssocField(0)
+	for _, names := range [][]string{layout.leading, layout.trailing} {
+		for _, name := range names {
+			if column, ok := assocColumns[name]; ok {
+				present |= column.field
+			}
+		}
+	}
+	return allAssocFields &^ present
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
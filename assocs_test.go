@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"fmt"
 	"log"
+	"net/netip"
 	"strings"
 	"testing"
 
@@ -19,60 +20,66 @@ func TestParseAssocs(t *testing.T) {
 	assocs, err := ParseAssocs(bufio.NewScanner(strings.NewReader(input)))
 	assert.NoError(t, err)
 	assert.EqualValues(t, &Assoc{
-		Assoc:   0,
-		Sock:    0,
-		Sty:     2,
-		Sst:     1,
-		St:      3,
-		Hbkt:    0,
-		AssocId: 60,
-		TxQueue: 0,
-		RxQueue: 496,
-		Uid:     0,
-		Inode:   188897,
-		LPort:   12345,
-		RPort:   54321,
-		LAddrs:  []string{"127.0.0.1"},
-		RAddrs:  []string{"127.0.0.2"},
-		Hbint:   30000,
-		Ins:     65535,
-		Outs:    65535,
-		Maxrt:   10,
-		T1x:     0,
-		T2x:     0,
-		Rtxc:    0,
-		Wmema:   1,
-		Wmemq:   0,
-		Sndbuf:  212992,
-		Rcvbuf:  212992,
+		Assoc:        0,
+		Sock:         0,
+		Sty:          2,
+		Sst:          1,
+		St:           3,
+		Hbkt:         0,
+		AssocId:      60,
+		TxQueue:      0,
+		RxQueue:      496,
+		Uid:          0,
+		Inode:        188897,
+		LPort:        12345,
+		RPort:        54321,
+		LAddrs:       []string{"127.0.0.1"},
+		RAddrs:       []string{"127.0.0.2"},
+		LAddrIPs:     []netip.Addr{netip.MustParseAddr("127.0.0.1")},
+		RAddrIPs:     []netip.Addr{netip.MustParseAddr("127.0.0.2")},
+		PrimaryRAddr: "127.0.0.2",
+		Hbint:        30000,
+		Ins:          65535,
+		Outs:         65535,
+		Maxrt:        10,
+		T1x:          0,
+		T2x:          0,
+		Rtxc:         0,
+		Wmema:        1,
+		Wmemq:        0,
+		Sndbuf:       212992,
+		Rcvbuf:       212992,
 	}, assocs[0])
 	assert.EqualValues(t, &Assoc{
-		Assoc:   0,
-		Sock:    0,
-		Sty:     2,
-		Sst:     1,
-		St:      3,
-		Hbkt:    0,
-		AssocId: 59,
-		TxQueue: 0,
-		RxQueue: 0,
-		Uid:     0,
-		Inode:   189472,
-		LPort:   54321,
-		RPort:   12345,
-		LAddrs:  []string{"127.0.0.2"},
-		RAddrs:  []string{"127.0.0.1"},
-		Hbint:   30000,
-		Ins:     65535,
-		Outs:    65535,
-		Maxrt:   10,
-		T1x:     0,
-		T2x:     0,
-		Rtxc:    0,
-		Wmema:   1,
-		Wmemq:   0,
-		Sndbuf:  212992,
-		Rcvbuf:  212992,
+		Assoc:        0,
+		Sock:         0,
+		Sty:          2,
+		Sst:          1,
+		St:           3,
+		Hbkt:         0,
+		AssocId:      59,
+		TxQueue:      0,
+		RxQueue:      0,
+		Uid:          0,
+		Inode:        189472,
+		LPort:        54321,
+		RPort:        12345,
+		LAddrs:       []string{"127.0.0.2"},
+		RAddrs:       []string{"127.0.0.1"},
+		LAddrIPs:     []netip.Addr{netip.MustParseAddr("127.0.0.2")},
+		RAddrIPs:     []netip.Addr{netip.MustParseAddr("127.0.0.1")},
+		PrimaryRAddr: "127.0.0.1",
+		Hbint:        30000,
+		Ins:          65535,
+		Outs:         65535,
+		Maxrt:        10,
+		T1x:          0,
+		T2x:          0,
+		Rtxc:         0,
+		Wmema:        1,
+		Wmemq:        0,
+		Sndbuf:       212992,
+		Rcvbuf:       212992,
 	}, assocs[1])
 }
 
@@ -85,60 +92,66 @@ func TestParseAssocsWithTabSeparated(t *testing.T) {
 	assocs, err := ParseAssocs(bufio.NewScanner(strings.NewReader(input)))
 	assert.NoError(t, err)
 	assert.EqualValues(t, &Assoc{
-		Assoc:   0,
-		Sock:    0,
-		Sty:     2,
-		Sst:     1,
-		St:      3,
-		Hbkt:    0,
-		AssocId: 60,
-		TxQueue: 0,
-		RxQueue: 496,
-		Uid:     0,
-		Inode:   188897,
-		LPort:   12345,
-		RPort:   54321,
-		LAddrs:  []string{"127.0.0.1"},
-		RAddrs:  []string{"127.0.0.2"},
-		Hbint:   30000,
-		Ins:     65535,
-		Outs:    65535,
-		Maxrt:   10,
-		T1x:     0,
-		T2x:     0,
-		Rtxc:    0,
-		Wmema:   1,
-		Wmemq:   0,
-		Sndbuf:  212992,
-		Rcvbuf:  212992,
+		Assoc:        0,
+		Sock:         0,
+		Sty:          2,
+		Sst:          1,
+		St:           3,
+		Hbkt:         0,
+		AssocId:      60,
+		TxQueue:      0,
+		RxQueue:      496,
+		Uid:          0,
+		Inode:        188897,
+		LPort:        12345,
+		RPort:        54321,
+		LAddrs:       []string{"127.0.0.1"},
+		RAddrs:       []string{"127.0.0.2"},
+		LAddrIPs:     []netip.Addr{netip.MustParseAddr("127.0.0.1")},
+		RAddrIPs:     []netip.Addr{netip.MustParseAddr("127.0.0.2")},
+		PrimaryRAddr: "127.0.0.2",
+		Hbint:        30000,
+		Ins:          65535,
+		Outs:         65535,
+		Maxrt:        10,
+		T1x:          0,
+		T2x:          0,
+		Rtxc:         0,
+		Wmema:        1,
+		Wmemq:        0,
+		Sndbuf:       212992,
+		Rcvbuf:       212992,
 	}, assocs[0])
 	assert.EqualValues(t, &Assoc{
-		Assoc:   0,
-		Sock:    0,
-		Sty:     2,
-		Sst:     1,
-		St:      3,
-		Hbkt:    0,
-		AssocId: 59,
-		TxQueue: 0,
-		RxQueue: 0,
-		Uid:     0,
-		Inode:   189472,
-		LPort:   54321,
-		RPort:   12345,
-		LAddrs:  []string{"127.0.0.2"},
-		RAddrs:  []string{"127.0.0.1"},
-		Hbint:   30000,
-		Ins:     65535,
-		Outs:    65535,
-		Maxrt:   10,
-		T1x:     0,
-		T2x:     0,
-		Rtxc:    0,
-		Wmema:   1,
-		Wmemq:   0,
-		Sndbuf:  212992,
-		Rcvbuf:  212992,
+		Assoc:        0,
+		Sock:         0,
+		Sty:          2,
+		Sst:          1,
+		St:           3,
+		Hbkt:         0,
+		AssocId:      59,
+		TxQueue:      0,
+		RxQueue:      0,
+		Uid:          0,
+		Inode:        189472,
+		LPort:        54321,
+		RPort:        12345,
+		LAddrs:       []string{"127.0.0.2"},
+		RAddrs:       []string{"127.0.0.1"},
+		LAddrIPs:     []netip.Addr{netip.MustParseAddr("127.0.0.2")},
+		RAddrIPs:     []netip.Addr{netip.MustParseAddr("127.0.0.1")},
+		PrimaryRAddr: "127.0.0.1",
+		Hbint:        30000,
+		Ins:          65535,
+		Outs:         65535,
+		Maxrt:        10,
+		T1x:          0,
+		T2x:          0,
+		Rtxc:         0,
+		Wmema:        1,
+		Wmemq:        0,
+		Sndbuf:       212992,
+		Rcvbuf:       212992,
 	}, assocs[1])
 }
 
@@ -150,61 +163,106 @@ func TestParseAssocs_WithMultipleAddresses(t *testing.T) {
 	assocs, err := ParseAssocs(bufio.NewScanner(strings.NewReader(input)))
 	assert.NoError(t, err)
 	assert.EqualValues(t, &Assoc{
-		Assoc:   0,
-		Sock:    0,
-		Sty:     2,
-		Sst:     1,
-		St:      3,
-		Hbkt:    0,
-		AssocId: 62,
-		TxQueue: 0,
-		RxQueue: 0,
-		Uid:     0,
-		Inode:   212110,
-		LPort:   54321,
-		RPort:   12345,
-		LAddrs:  []string{"127.0.0.10", "127.0.0.20"},
-		RAddrs:  []string{"127.0.0.1", "127.0.0.2"},
-		Hbint:   30000,
-		Ins:     65535,
-		Outs:    65535,
-		Maxrt:   10,
-		T1x:     0,
-		T2x:     0,
-		Rtxc:    0,
-		Wmema:   1,
-		Wmemq:   0,
-		Sndbuf:  212992, Rcvbuf: 212992}, assocs[0])
+		Assoc:        0,
+		Sock:         0,
+		Sty:          2,
+		Sst:          1,
+		St:           3,
+		Hbkt:         0,
+		AssocId:      62,
+		TxQueue:      0,
+		RxQueue:      0,
+		Uid:          0,
+		Inode:        212110,
+		LPort:        54321,
+		RPort:        12345,
+		LAddrs:       []string{"127.0.0.10", "127.0.0.20"},
+		RAddrs:       []string{"127.0.0.1", "127.0.0.2"},
+		LAddrIPs:     []netip.Addr{netip.MustParseAddr("127.0.0.10"), netip.MustParseAddr("127.0.0.20")},
+		RAddrIPs:     []netip.Addr{netip.MustParseAddr("127.0.0.1"), netip.MustParseAddr("127.0.0.2")},
+		PrimaryRAddr: "127.0.0.1",
+		Hbint:        30000,
+		Ins:          65535,
+		Outs:         65535,
+		Maxrt:        10,
+		T1x:          0,
+		T2x:          0,
+		Rtxc:         0,
+		Wmema:        1,
+		Wmemq:        0,
+		Sndbuf:       212992, Rcvbuf: 212992}, assocs[0])
 	assert.EqualValues(t, &Assoc{
-		Assoc:   0,
-		Sock:    0,
-		Sty:     2,
-		Sst:     1,
-		St:      3,
-		Hbkt:    0,
-		AssocId: 63,
-		TxQueue: 0,
-		RxQueue: 0,
-		Uid:     0,
-		Inode:   212095,
-		LPort:   12345,
-		RPort:   54321,
-		LAddrs:  []string{"127.0.0.1", "127.0.0.2"},
-		RAddrs:  []string{"127.0.0.10", "127.0.0.20"},
-		Hbint:   30000,
-		Ins:     65535,
-		Outs:    65535,
-		Maxrt:   10,
-		T1x:     0,
-		T2x:     0,
-		Rtxc:    0,
-		Wmema:   1,
-		Wmemq:   0,
-		Sndbuf:  212992,
-		Rcvbuf:  212992,
+		Assoc:        0,
+		Sock:         0,
+		Sty:          2,
+		Sst:          1,
+		St:           3,
+		Hbkt:         0,
+		AssocId:      63,
+		TxQueue:      0,
+		RxQueue:      0,
+		Uid:          0,
+		Inode:        212095,
+		LPort:        12345,
+		RPort:        54321,
+		LAddrs:       []string{"127.0.0.1", "127.0.0.2"},
+		RAddrs:       []string{"127.0.0.10", "127.0.0.20"},
+		LAddrIPs:     []netip.Addr{netip.MustParseAddr("127.0.0.1"), netip.MustParseAddr("127.0.0.2")},
+		RAddrIPs:     []netip.Addr{netip.MustParseAddr("127.0.0.10"), netip.MustParseAddr("127.0.0.20")},
+		PrimaryRAddr: "127.0.0.10",
+		Hbint:        30000,
+		Ins:          65535,
+		Outs:         65535,
+		Maxrt:        10,
+		T1x:          0,
+		T2x:          0,
+		Rtxc:         0,
+		Wmema:        1,
+		Wmemq:        0,
+		Sndbuf:       212992,
+		Rcvbuf:       212992,
 	}, assocs[1])
 }
 
+func TestParseAssocs_WithPrimaryPathNotFirst(t *testing.T) {
+	input := `ASSOC     SOCK   STY SST ST HBKT ASSOC-ID TX_QUEUE RX_QUEUE UID INODE LPORT RPORT LADDRS <-> RADDRS HBINT INS OUTS MAXRT T1X T2X RTXC wmema wmemq sndbuf rcvbuf
+	0        0 2   1   3  0      62        0        0       0 212110 54321 12345  127.0.0.10 *127.0.0.20 <-> 127.0.0.1 127.0.0.2 *127.0.0.3    30000 65535 65535   10    0    0        0        1        0   212992   212992
+	0        0 2   1   3  0      63        0        0       0 212095 12345 54321  127.0.0.1 127.0.0.2 <-> 127.0.0.10 127.0.0.20    30000 65535 65535   10    0    0        0        1        0   212992   212992
+`
+	assocs, err := ParseAssocs(bufio.NewScanner(strings.NewReader(input)))
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"127.0.0.10", "127.0.0.20"}, assocs[0].LAddrs)
+	assert.Equal(t, []string{"127.0.0.1", "127.0.0.2", "127.0.0.3"}, assocs[0].RAddrs)
+	assert.Equal(t, "127.0.0.20", assocs[0].PrimaryLAddr)
+	assert.Equal(t, "127.0.0.3", assocs[0].PrimaryRAddr)
+
+	assert.Equal(t, []string{"127.0.0.10", "127.0.0.20"}, assocs[1].RAddrs)
+	assert.Empty(t, assocs[1].PrimaryLAddr)
+	assert.Empty(t, assocs[1].PrimaryRAddr)
+}
+
+func TestParseAssocs_WithIPv6Addresses(t *testing.T) {
+	input := `ASSOC     SOCK   STY SST ST HBKT ASSOC-ID TX_QUEUE RX_QUEUE UID INODE LPORT RPORT LADDRS <-> RADDRS HBINT INS OUTS MAXRT T1X T2X RTXC wmema wmemq sndbuf rcvbuf
+	0        0 2   1   3  0      62        0        0       0 212110 54321 12345  2001:0db8:0000:0000:0000:0000:0000:0001 fe80::1%eth0 <-> *2001:db8::2 127.0.0.1    30000 65535 65535   10    0    0        0        1        0   212992   212992
+`
+	assocs, err := ParseAssocs(bufio.NewScanner(strings.NewReader(input)))
+	assert.NoError(t, err)
+	assert.Equal(t, []netip.Addr{netip.MustParseAddr("2001:db8::1"), netip.MustParseAddr("fe80::1%eth0")}, assocs[0].LAddrIPs)
+	assert.Equal(t, "eth0", assocs[0].LAddrIPs[1].Zone())
+	assert.Equal(t, []netip.Addr{netip.MustParseAddr("2001:db8::2"), netip.MustParseAddr("127.0.0.1")}, assocs[0].RAddrIPs)
+	assert.Equal(t, []string{"2001:0db8:0000:0000:0000:0000:0000:0001", "fe80::1%eth0"}, assocs[0].LAddrs)
+}
+
+func TestParseAssocs_WithInvalidAddress(t *testing.T) {
+	input := `ASSOC     SOCK   STY SST ST HBKT ASSOC-ID TX_QUEUE RX_QUEUE UID INODE LPORT RPORT LADDRS <-> RADDRS HBINT INS OUTS MAXRT T1X T2X RTXC wmema wmemq sndbuf rcvbuf
+	0        0 2   1   3  0      62        0        0       0 212110 54321 12345  127.0.0.1 <-> *127.0.0.300    30000 65535 65535   10    0    0        0        1        0   212992   212992
+`
+	_, err := ParseAssocs(bufio.NewScanner(strings.NewReader(input)))
+	assert.ErrorIs(t, err, ErrInvalidAddress)
+	assert.Contains(t, err.Error(), "RADDRS at line #2")
+}
+
 func TestParseAssocs_WithInvalidInput(t *testing.T) {
 	input := `ASSOC     SOCK   STY SST ST HBKT ASSOC-ID TX_QUEUE RX_QUEUE UID INODE LPORT RPORT LADDRS <-> RADDRS HBINT INS OUTS MAXRT T1X T2X RTXC wmema wmemq sndbuf rcvbuf`
 
@@ -224,6 +282,158 @@ func TestParseAssocs_WithInsufficientItems(t *testing.T) {
 	assert.Contains(t, err.Error(), "at line #2")
 }
 
+func TestParseAssocs_WithReorderedColumns(t *testing.T) {
+	input := `SOCK ASSOC STY SST ST HBKT ASSOC-ID TX_QUEUE RX_QUEUE UID INODE NEWCOL LPORT RPORT LADDRS <-> RADDRS HBINT INS OUTS MAXRT T1X T2X RTXC wmemq wmema rcvbuf sndbuf
+     1        2 2   1   3  0      60        0      496       0 188897 999 12345 54321  127.0.0.1 <-> *127.0.0.2     30000 65535 65535   10    0    0        0        3        4   5   6
+`
+	assocs, err := ParseAssocs(bufio.NewScanner(strings.NewReader(input)))
+	assert.NoError(t, err)
+	assert.EqualValues(t, &Assoc{
+		Assoc:        2,
+		Sock:         1,
+		Sty:          2,
+		Sst:          1,
+		St:           3,
+		Hbkt:         0,
+		AssocId:      60,
+		TxQueue:      0,
+		RxQueue:      496,
+		Uid:          0,
+		Inode:        188897,
+		LPort:        12345,
+		RPort:        54321,
+		LAddrs:       []string{"127.0.0.1"},
+		RAddrs:       []string{"127.0.0.2"},
+		LAddrIPs:     []netip.Addr{netip.MustParseAddr("127.0.0.1")},
+		RAddrIPs:     []netip.Addr{netip.MustParseAddr("127.0.0.2")},
+		PrimaryRAddr: "127.0.0.2",
+		Hbint:        30000,
+		Ins:          65535,
+		Outs:         65535,
+		Maxrt:        10,
+		T1x:          0,
+		T2x:          0,
+		Rtxc:         0,
+		Wmema:        4,
+		Wmemq:        3,
+		Sndbuf:       6,
+		Rcvbuf:       5,
+	}, assocs[0])
+}
+
+func TestParseAssocs_WithInvalidHeader(t *testing.T) {
+	input := `ASSOC     SOCK   STY SST ST HBKT ASSOC-ID TX_QUEUE RX_QUEUE UID INODE LPORT RPORT HBINT INS OUTS MAXRT T1X T2X RTXC wmema wmemq sndbuf rcvbuf
+     0        0 2   1   3  0      60        0      496       0 188897 12345 54321  127.0.0.1 <-> *127.0.0.2     30000 65535 65535   10    0    0        0        1        0   212992   212992
+`
+	_, err := ParseAssocs(bufio.NewScanner(strings.NewReader(input)))
+	assert.ErrorIs(t, err, ErrInvalidAssocsFormat)
+	assert.Contains(t, err.Error(), "header at line #1")
+}
+
+func TestParseAssocs_WithoutMemoryColumns(t *testing.T) {
+	input := `ASSOC     SOCK   STY SST ST HBKT ASSOC-ID TX_QUEUE RX_QUEUE UID INODE LPORT RPORT LADDRS <-> RADDRS HBINT INS OUTS MAXRT T1X T2X RTXC
+     0        0 2   1   3  0      60        0      496       0 188897 12345 54321  127.0.0.1 <-> *127.0.0.2     30000 10 10   10    1    2        3
+`
+	assocs, err := ParseAssocs(bufio.NewScanner(strings.NewReader(input)))
+	assert.NoError(t, err)
+	assert.EqualValues(t, &Assoc{
+		Assoc:        0,
+		Sock:         0,
+		Sty:          2,
+		Sst:          1,
+		St:           3,
+		Hbkt:         0,
+		AssocId:      60,
+		TxQueue:      0,
+		RxQueue:      496,
+		Uid:          0,
+		Inode:        188897,
+		LPort:        12345,
+		RPort:        54321,
+		LAddrs:       []string{"127.0.0.1"},
+		RAddrs:       []string{"127.0.0.2"},
+		LAddrIPs:     []netip.Addr{netip.MustParseAddr("127.0.0.1")},
+		RAddrIPs:     []netip.Addr{netip.MustParseAddr("127.0.0.2")},
+		PrimaryRAddr: "127.0.0.2",
+		Hbint:        30000,
+		Ins:          10,
+		Outs:         10,
+		Maxrt:        10,
+		T1x:          1,
+		T2x:          2,
+		Rtxc:         3,
+		Absent:       AssocFieldWmema | AssocFieldWmemq | AssocFieldSndbuf | AssocFieldRcvbuf,
+	}, assocs[0])
+	assert.True(t, assocs[0].Has(AssocFieldRtxc))
+	assert.False(t, assocs[0].Has(AssocFieldSndbuf))
+}
+
+func TestParseAssocs_WithOldestLayout(t *testing.T) {
+	input := `ASSOC     SOCK   STY SST ST HBKT ASSOC-ID TX_QUEUE RX_QUEUE UID INODE LPORT RPORT LADDRS <-> RADDRS
+     0        0 2   1   3  0      60        0      496       0 188897 12345 54321  127.0.0.1 127.0.0.2 <-> *127.0.0.3 127.0.0.4
+`
+	assocs, err := ParseAssocs(bufio.NewScanner(strings.NewReader(input)))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"127.0.0.1", "127.0.0.2"}, assocs[0].LAddrs)
+	assert.Equal(t, []string{"127.0.0.3", "127.0.0.4"}, assocs[0].RAddrs)
+	assert.EqualValues(t, 60, assocs[0].AssocId)
+	assert.True(t, assocs[0].Has(AssocFieldRPort))
+	for _, field := range []AssocField{AssocFieldHbint, AssocFieldIns, AssocFieldOuts, AssocFieldMaxrt, AssocFieldT1x, AssocFieldT2x, AssocFieldRtxc, AssocFieldWmema, AssocFieldWmemq, AssocFieldSndbuf, AssocFieldRcvbuf} {
+		assert.False(t, assocs[0].Has(field))
+	}
+}
+
+func TestParseAssocs_DetectLayoutWithoutHeader(t *testing.T) {
+	tests := []struct {
+		input  string
+		absent AssocField
+	}{
+		{
+			input:  `0 0 2 1 3 0 60 0 496 0 188897 12345 54321 127.0.0.1 <-> *127.0.0.2 30000 65535 65535 10 0 0 0 1 0 212992 212992`,
+			absent: 0,
+		},
+		{
+			input:  `0 0 2 1 3 0 60 0 496 0 188897 12345 54321 127.0.0.1 <-> *127.0.0.2 30000 65535 65535 10 0 0 0`,
+			absent: AssocFieldWmema | AssocFieldWmemq | AssocFieldSndbuf | AssocFieldRcvbuf,
+		},
+		{
+			input:  `0 0 2 1 3 0 60 0 496 0 188897 12345 54321 127.0.0.1 <-> *127.0.0.2 127.0.0.3`,
+			absent: AssocFieldHbint | AssocFieldIns | AssocFieldOuts | AssocFieldMaxrt | AssocFieldT1x | AssocFieldT2x | AssocFieldRtxc | AssocFieldWmema | AssocFieldWmemq | AssocFieldSndbuf | AssocFieldRcvbuf,
+		},
+	}
+
+	for _, test := range tests {
+		assocs, err := ParseAssocs(bufio.NewScanner(strings.NewReader(test.input)), true)
+		assert.NoError(t, err)
+		assert.EqualValues(t, 60, assocs[0].AssocId)
+		assert.EqualValues(t, 54321, assocs[0].RPort)
+		assert.Equal(t, test.absent, assocs[0].Absent)
+	}
+}
+
+func TestAssocScanner(t *testing.T) {
+	input := `ASSOC     SOCK   STY SST ST HBKT ASSOC-ID TX_QUEUE RX_QUEUE UID INODE LPORT RPORT LADDRS <-> RADDRS HBINT INS OUTS MAXRT T1X T2X RTXC wmema wmemq sndbuf rcvbuf
+     0        0 2   1   3  0      60        0      496       0 188897 12345 54321  127.0.0.1 <-> *127.0.0.2     30000 65535 65535   10    0    0        0        1        0   212992   212992
+
+     0        0 2   1   3  0      59        0        0       0 189472 54321 12345  127.0.0.2 <-> *127.0.0.1     30000 65535 65535   10    0    0        0        1        0   212992   212992
+     0        0 2   1   3  0      58        0        0       0 189473 54321 12345  127.0.0.2 <-> *127.0.0.1     30000 65535 65535   10    0    0        0        1        0   212992
+`
+	scanner := NewAssocScanner(bufio.NewScanner(strings.NewReader(input)))
+
+	assert.True(t, scanner.Next())
+	assert.EqualValues(t, 60, scanner.Record().AssocId)
+	assert.True(t, scanner.Next())
+	assert.EqualValues(t, 59, scanner.Record().AssocId)
+	assert.NoError(t, scanner.Err())
+
+	assert.False(t, scanner.Next())
+	assert.Nil(t, scanner.Record())
+	assert.ErrorIs(t, scanner.Err(), ErrInsufficientNumberOfAssocItems)
+	assert.Contains(t, scanner.Err().Error(), "at line #5")
+
+	assert.False(t, scanner.Next())
+}
+
 func ExampleParseAssocs() {
 	input := `ASSOC     SOCK   STY SST ST HBKT ASSOC-ID TX_QUEUE RX_QUEUE UID INODE LPORT RPORT LADDRS <-> RADDRS HBINT INS OUTS MAXRT T1X T2X RTXC wmema wmemq sndbuf rcvbuf
      0        0 2   1   3  0      60        0      496       0 188897 12345 54321  127.0.0.1 <-> *127.0.0.2     30000 65535 65535   10    0    0        0        1        0   212992   212992
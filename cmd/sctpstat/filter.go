@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	parser "github.com/moznion/go-sctp-proc-parser"
+)
+
+// filter selects the records like the filters of ss(8). Empty conditions match everything.
+type filter struct {
+	ports           []int64
+	prefixes        []netip.Prefix
+	assocStates     []parser.AssocState
+	socketStates    []parser.SocketState
+	transportStates []parser.TransportState
+}
+
+func newFilter(ports string, addrs string) (*filter, error) {
+	f := &filter{}
+
+	for _, v := range splitList(ports) {
+		port, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid port: %s", v)
+		}
+		f.ports = append(f.ports, port)
+	}
+
+	for _, v := range splitList(addrs) {
+		prefix, err := parsePrefix(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address: %s", v)
+		}
+		f.prefixes = append(f.prefixes, prefix)
+	}
+
+	return f, nil
+}
+
+func (f *filter) setAssocStates(states string) error {
+	for _, v := range splitList(states) {
+		state, err := parser.ParseAssocState(v)
+		if err != nil {
+			return err
+		}
+		f.assocStates = append(f.assocStates, state)
+	}
+	return nil
+}
+
+func (f *filter) setSocketStates(states string) error {
+	for _, v := range splitList(states) {
+		state, err := parser.ParseSocketState(v)
+		if err != nil {
+			return err
+		}
+		f.socketStates = append(f.socketStates, state)
+	}
+	return nil
+}
+
+func (f *filter) setTransportStates(states string) error {
+	for _, v := range splitList(states) {
+		state, err := parser.ParseTransportState(v)
+		if err != nil {
+			return err
+		}
+		f.transportStates = append(f.transportStates, state)
+	}
+	return nil
+}
+
+func (f *filter) assocs(assocs []*parser.Assoc) []*parser.Assoc {
+	filtered := make([]*parser.Assoc, 0, len(assocs))
+	for _, assoc := range assocs {
+		if f.matchPorts(assoc.LPort, assoc.RPort) &&
+			f.matchAddrs(assoc.LAddrIPs, assoc.RAddrIPs) &&
+			contains(f.assocStates, assoc.St) {
+			filtered = append(filtered, assoc)
+		}
+	}
+	return filtered
+}
+
+func (f *filter) eps(epses []*parser.EPS) []*parser.EPS {
+	filtered := make([]*parser.EPS, 0, len(epses))
+	for _, eps := range epses {
+		if f.matchPorts(eps.LPort) &&
+			f.matchAddrs(eps.LAddrIPs) &&
+			contains(f.socketStates, eps.Sst) {
+			filtered = append(filtered, eps)
+		}
+	}
+	return filtered
+}
+
+// paths returns the associations that have the matched paths; the views only contain the matched paths.
+func (f *filter) paths(views []*parser.AssociationView) []*parser.AssociationView {
+	filtered := make([]*parser.AssociationView, 0, len(views))
+	for _, view := range views {
+		if !f.matchPorts(view.Assoc.LPort, view.Assoc.RPort) {
+			continue
+		}
+
+		paths := make([]*parser.Remaddr, 0, len(view.Paths))
+		for _, path := range view.Paths {
+			// a path matches with the local addresses of the association or its own remote address
+			if f.matchAddrs(view.Assoc.LAddrIPs, []netip.Addr{path.AddrIP}) && contains(f.transportStates, path.State) {
+				paths = append(paths, path)
+			}
+		}
+		if len(paths) > 0 {
+			filtered = append(filtered, &parser.AssociationView{Assoc: view.Assoc, Paths: paths})
+		}
+	}
+	return filtered
+}
+
+func (f *filter) matchPorts(ports ...int64) bool {
+	if len(f.ports) <= 0 {
+		return true
+	}
+	for _, port := range ports {
+		if contains(f.ports, port) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *filter) matchAddrs(addrLists ...[]netip.Addr) bool {
+	if len(f.prefixes) <= 0 {
+		return true
+	}
+	for _, addrs := range addrLists {
+		for _, addr := range addrs {
+			for _, prefix := range f.prefixes {
+				if prefix.Contains(addr.Unmap()) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// contains returns whether the values contain v. Empty values contain everything.
+func contains[T comparable](values []T, v T) bool {
+	if len(values) <= 0 {
+		return true
+	}
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+// parsePrefix parses a CIDR prefix or a single address.
+func parsePrefix(v string) (netip.Prefix, error) {
+	if strings.Contains(v, "/") {
+		prefix, err := netip.ParsePrefix(v)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		return prefix.Masked(), nil
+	}
+
+	addr, err := netip.ParseAddr(v)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+func splitList(v string) []string {
+	values := make([]string, 0)
+	for _, value := range strings.Split(v, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"net/netip"
+	"testing"
+
+	parser "github.com/moznion/go-sctp-proc-parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter_MatchAddrs(t *testing.T) {
+	f, err := newFilter("", "10.0.0.0/8,2001:db8::1")
+	assert.NoError(t, err)
+
+	assert.True(t, f.matchAddrs([]netip.Addr{netip.MustParseAddr("10.1.2.3")}))
+	assert.True(t, f.matchAddrs([]netip.Addr{netip.MustParseAddr("::ffff:10.1.2.3")}))
+	assert.True(t, f.matchAddrs(nil, []netip.Addr{netip.MustParseAddr("2001:db8::1")}))
+	assert.False(t, f.matchAddrs([]netip.Addr{netip.MustParseAddr("2001:db8::2"), netip.MustParseAddr("192.168.0.1")}))
+}
+
+func TestFilter_WithoutConditions(t *testing.T) {
+	f, err := newFilter("", "")
+	assert.NoError(t, err)
+
+	assocs := []*parser.Assoc{{AssocId: 1}, {AssocId: 2}}
+	assert.Equal(t, assocs, f.assocs(assocs))
+}
+
+func TestNewFilter_WithInvalidAddress(t *testing.T) {
+	_, err := newFilter("", "10.0.0.0/33")
+	assert.EqualError(t, err, "invalid address: 10.0.0.0/33")
+}
@@ -0,0 +1,176 @@
+// Command sctpstat prints the SCTP associations, endpoints and paths in human-readable tables.
+//
+// Usage:
+//
+//	sctpstat [flags] [assocs|eps|paths]
+//
+// The filters work like ss(8); e.g. `sctpstat -port 3868 -state ESTABLISHED` prints the established associations on the port 3868.
+// `-output` selects a machine-readable format instead of the table; e.g. `sctpstat -output jsonl paths`.
+// `-source` selects where the records are read from; the proc filesystem (default), sock_diag (`netlink`), or a directory of the captured files.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	parser "github.com/moznion/go-sctp-proc-parser"
+	"github.com/moznion/go-sctp-proc-parser/sockdiag"
+)
+
+const (
+	viewAssocs = "assocs"
+	viewEPS    = "eps"
+	viewPaths  = "paths"
+
+	sourceProc    = "proc"
+	sourceNetlink = "netlink"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "sctpstat: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout io.Writer, stderr io.Writer) error {
+	flags := flag.NewFlagSet("sctpstat", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: sctpstat [flags] [%s|%s|%s]\n", viewAssocs, viewEPS, viewPaths)
+		flags.PrintDefaults()
+	}
+
+	procRoot := flags.String("proc", parser.DefaultProcRoot, "mount point of the proc filesystem")
+	from := flags.String("source", sourceProc, "where the records are read from; proc, netlink (sock_diag), or the directory of the files captured from /proc/net/sctp")
+	ports := flags.String("port", "", "comma-separated ports; matches either the local or the remote port")
+	addrs := flags.String("addr", "", "comma-separated addresses or CIDR prefixes; matches either a local or a remote address")
+	states := flags.String("state", "", "comma-separated states; association states for assocs, socket states for eps and transport states for paths")
+	processes := flags.Bool("p", false, "show the processes that have the sockets open")
+	output := flags.String("output", "table", "output format; table, json, jsonl, csv or yaml")
+	hz := flags.Int("hz", parser.DefaultHZ, "CONFIG_HZ of the kernel, with which RTO is converted between jiffies and milliseconds")
+	passwd := flags.String("passwd", "", "passwd file to resolve the user names from (e.g. "+parser.DefaultPasswdPath+"); UIDs are shown if empty")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	view := viewAssocs
+	switch flags.NArg() {
+	case 0:
+	case 1:
+		view = flags.Arg(0)
+	default:
+		flags.Usage()
+		return fmt.Errorf("too many arguments: %s", strings.Join(flags.Args(), " "))
+	}
+
+	f, err := newFilter(*ports, *addrs)
+	if err != nil {
+		return err
+	}
+
+	var enc parser.Encoding
+	if *output != "table" {
+		if enc, err = parser.ParseEncoding(*output); err != nil {
+			return err
+		}
+	}
+
+	fs := parser.NewProcFS(*procRoot)
+	source := newSource(*from, fs, *hz)
+	ctx := context.Background()
+	var users *parser.UserResolver
+	if *passwd != "" {
+		users = parser.NewUserResolver(*passwd)
+	}
+
+	switch view {
+	case viewAssocs:
+		if err := f.setAssocStates(*states); err != nil {
+			return err
+		}
+		assocs, err := source.Assocs(ctx)
+		if err != nil {
+			return err
+		}
+		if *processes {
+			if err := annotateOwners(fs, func(o parser.SocketOwners) { o.AnnotateAssocs(assocs) }); err != nil {
+				return err
+			}
+		}
+		if users != nil {
+			if err := users.AnnotateAssocs(assocs); err != nil {
+				return err
+			}
+		}
+		if enc != "" {
+			return parser.EncodeAssocs(stdout, enc, f.assocs(assocs))
+		}
+		return writeAssocs(stdout, f.assocs(assocs), *processes)
+	case viewEPS:
+		if err := f.setSocketStates(*states); err != nil {
+			return err
+		}
+		epses, err := source.Endpoints(ctx)
+		if err != nil {
+			return err
+		}
+		if *processes {
+			if err := annotateOwners(fs, func(o parser.SocketOwners) { o.AnnotateEPS(epses) }); err != nil {
+				return err
+			}
+		}
+		if users != nil {
+			if err := users.AnnotateEPS(epses); err != nil {
+				return err
+			}
+		}
+		if enc != "" {
+			return parser.EncodeEPS(stdout, enc, f.eps(epses))
+		}
+		return writeEPS(stdout, f.eps(epses), *processes)
+	case viewPaths:
+		if err := f.setTransportStates(*states); err != nil {
+			return err
+		}
+		snapshot, err := parser.ReadSnapshotFrom(ctx, source, parser.DefaultSnapshotAttempts)
+		if err != nil {
+			return err
+		}
+		if enc != "" {
+			return parser.EncodeAssociationViews(stdout, enc, f.paths(snapshot.Associations))
+		}
+		return writePaths(stdout, f.paths(snapshot.Associations), *hz)
+	default:
+		flags.Usage()
+		return fmt.Errorf("unknown view: %s", view)
+	}
+}
+
+// newSource returns the source of the records; the processes and the network namespaces are always read from the proc filesystem.
+// hz is the kernel's CONFIG_HZ, with which sock_diag's RTO is converted into jiffies as the proc filesystem reports it.
+func newSource(from string, fs parser.ProcFS, hz int) parser.Source {
+	switch from {
+	case sourceProc:
+		return fs
+	case sourceNetlink:
+		source := sockdiag.NewSource(fs)
+		source.HZ = hz
+		return source
+	default:
+		return parser.NewDirSource(from)
+	}
+}
+
+func annotateOwners(fs parser.ProcFS, annotate func(o parser.SocketOwners)) error {
+	owners, err := fs.ReadSocketOwners()
+	if err != nil {
+		return err
+	}
+	annotate(owners)
+	return nil
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	parser "github.com/moznion/go-sctp-proc-parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeProcFiles(t *testing.T, root string) {
+	t.Helper()
+
+	files := map[string]string{
+		"net/sctp/assocs": ` ASSOC     SOCK   STY SST ST HBKT ASSOC-ID TX_QUEUE RX_QUEUE UID INODE LPORT RPORT LADDRS <-> RADDRS HBINT INS OUTS MAXRT T1X T2X RTXC wmema wmemq sndbuf rcvbuf
+       0        0 2   1   3  0      60      100      496       0 188897 3868 54321  *10.0.0.1 <-> *10.0.0.2 10.0.0.3 	   30000 65535 65535   10    1    0        4        1        0   212992   212992
+       0        0 2   1   4  0      61       50        0    1000 189472 2905 54322  10.0.0.1 <-> *192.168.0.4 	   30000 65535 65535   10    0    2        6        1        0   212992   212992
+`,
+		"net/sctp/remaddr": `ADDR ASSOC_ID HB_ACT RTO MAX_PATH_RTX REM_ADDR_RTX START STATE
+10.0.0.2  60 1 3000 5 0 0 2
+10.0.0.3  60 1 1000 5 2 0 0
+192.168.0.4  61 0 3000 5 0 0 2
+`,
+		"net/sctp/eps": ` ENDPT     SOCK   STY SST HBKT LPORT   UID INODE LADDRS
+       0        0 2   10  24   3868     0 227065 10.0.0.1
+       0        0 2   10  16   2905  1000 232851 10.0.0.1 10.0.0.5
+`,
+	}
+	for name, contents := range files {
+		path := filepath.Join(root, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.NoError(t, os.WriteFile(path, []byte(contents), 0o644))
+	}
+}
+
+func runSctpstat(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	root := t.TempDir()
+	writeProcFiles(t, root)
+
+	stdout := &bytes.Buffer{}
+	err := run(append([]string{"-proc", root}, args...), stdout, &bytes.Buffer{})
+	return stdout.String(), err
+}
+
+func TestRun_Assocs(t *testing.T) {
+	out, err := runSctpstat(t)
+	assert.NoError(t, err)
+	assert.Equal(t, `ASSOC-ID  STATE             TYPE        LPORT  LADDRS     RPORT  RADDRS              TX-Q  RX-Q  RTXC  UID   INODE
+60        ESTABLISHED       ONE_TO_ONE  3868   *10.0.0.1  54321  *10.0.0.2,10.0.0.3  100   496   4     0     188897
+61        SHUTDOWN_PENDING  ONE_TO_ONE  2905   10.0.0.1   54322  *192.168.0.4        50    0     6     1000  189472
+`, out)
+}
+
+func TestRun_AssocsWithFilters(t *testing.T) {
+	out, err := runSctpstat(t, "-port", "54322", "-addr", "192.168.0.0/24", "-state", "shutdown_pending")
+	assert.NoError(t, err)
+	assert.Equal(t, `ASSOC-ID  STATE             TYPE        LPORT  LADDRS    RPORT  RADDRS        TX-Q  RX-Q  RTXC  UID   INODE
+61        SHUTDOWN_PENDING  ONE_TO_ONE  2905   10.0.0.1  54322  *192.168.0.4  50    0     6     1000  189472
+`, out)
+
+	out, err = runSctpstat(t, "-state", "CLOSED")
+	assert.NoError(t, err)
+	assert.Equal(t, "ASSOC-ID  STATE  TYPE  LPORT  LADDRS  RPORT  RADDRS  TX-Q  RX-Q  RTXC  UID  INODE\n", out)
+}
+
+func TestRun_EPS(t *testing.T) {
+	out, err := runSctpstat(t, "-addr", "10.0.0.5", "eps")
+	assert.NoError(t, err)
+	assert.Equal(t, `LPORT  STATE   TYPE        LADDRS             UID   INODE
+2905   LISTEN  ONE_TO_ONE  10.0.0.1,10.0.0.5  1000  232851
+`, out)
+}
+
+func TestRun_Paths(t *testing.T) {
+	out, err := runSctpstat(t, "paths")
+	assert.NoError(t, err)
+	assert.Equal(t, `ASSOC-ID  LPORT  RPORT  ADDR          STATE     RTO(ms)  HB   RTX
+60        3868   54321  *10.0.0.2     ACTIVE    3000     on   0
+60        3868   54321  10.0.0.3      INACTIVE  1000     on   2
+61        2905   54322  *192.168.0.4  ACTIVE    3000     off  0
+`, out)
+
+	out, err = runSctpstat(t, "-state", "inactive", "paths")
+	assert.NoError(t, err)
+	assert.Equal(t, `ASSOC-ID  LPORT  RPORT  ADDR      STATE     RTO(ms)  HB  RTX
+60        3868   54321  10.0.0.3  INACTIVE  1000     on  2
+`, out)
+	out, err = runSctpstat(t, "-hz", "250", "-state", "inactive", "paths")
+	assert.NoError(t, err)
+	assert.Equal(t, `ASSOC-ID  LPORT  RPORT  ADDR      STATE     RTO(ms)  HB  RTX
+60        3868   54321  10.0.0.3  INACTIVE  4000     on  2
+`, out)
+}
+
+func TestRun_WithUserNames(t *testing.T) {
+	passwd := filepath.Join(t.TempDir(), "passwd")
+	assert.NoError(t, os.WriteFile(passwd, []byte("root:x:0:0:root:/root:/bin/sh\ndiameter:x:1000:1000::/home/diameter:/bin/sh\n"), 0o644))
+
+	out, err := runSctpstat(t, "-passwd", passwd, "eps")
+	assert.NoError(t, err)
+	assert.Equal(t, `LPORT  STATE   TYPE        LADDRS             UID       INODE
+3868   LISTEN  ONE_TO_ONE  10.0.0.1           root      227065
+2905   LISTEN  ONE_TO_ONE  10.0.0.1,10.0.0.5  diameter  232851
+`, out)
+}
+
+func TestRun_WithOutput(t *testing.T) {
+	out, err := runSctpstat(t, "-output", "csv", "-port", "3868", "eps")
+	assert.NoError(t, err)
+	assert.Equal(t, `endpt,sock,sty,sst,hbkt,lport,uid,inode,laddrs,owners,user_name,container_id,pod_uid
+0,0,ONE_TO_ONE,LISTEN,24,3868,0,227065,10.0.0.1,,,,
+`, out)
+
+	out, err = runSctpstat(t, "--output=jsonl", "-state", "inactive", "paths")
+	assert.NoError(t, err)
+	assert.Contains(t, out, `"paths":[{"addr":"10.0.0.3","assoc_id":60,"hb_act":1,"rto":1000,"max_path_rtx":5,"rem_addr_rtx":2,"start":0,"state":"INACTIVE"}]}`)
+	assert.Equal(t, 1, strings.Count(out, "\n"))
+}
+
+func TestRun_WithCapturedFiles(t *testing.T) {
+	root := t.TempDir()
+	writeProcFiles(t, root)
+
+	stdout := &bytes.Buffer{}
+	err := run([]string{"-source", filepath.Join(root, "net", "sctp"), "-state", "inactive", "paths"}, stdout, &bytes.Buffer{})
+	assert.NoError(t, err)
+	assert.Contains(t, stdout.String(), "10.0.0.3")
+	assert.NotContains(t, stdout.String(), "10.0.0.2")
+
+	err = run([]string{"-source", t.TempDir(), "eps"}, stdout, &bytes.Buffer{})
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestRun_WithInvalidArguments(t *testing.T) {
+	_, err := runSctpstat(t, "unknown")
+	assert.EqualError(t, err, "unknown view: unknown")
+
+	_, err = runSctpstat(t, "-port", "x")
+	assert.EqualError(t, err, "invalid port: x")
+
+	_, err = runSctpstat(t, "-state", "x")
+	assert.Error(t, err)
+
+	_, err = runSctpstat(t, "-output", "xml")
+	assert.ErrorIs(t, err, parser.ErrUnknownEncoding)
+
+	_, err = runSctpstat(t, "assocs", "eps")
+	assert.Error(t, err)
+}
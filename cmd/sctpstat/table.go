@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	parser "github.com/moznion/go-sctp-proc-parser"
+)
+
+func newTableWriter(w io.Writer) *tabwriter.Writer {
+	return tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+}
+
+func writeAssocs(w io.Writer, assocs []*parser.Assoc, withProcesses bool) error {
+	tw := newTableWriter(w)
+
+	header := "ASSOC-ID\tSTATE\tTYPE\tLPORT\tLADDRS\tRPORT\tRADDRS\tTX-Q\tRX-Q\tRTXC\tUID\tINODE"
+	if withProcesses {
+		header += "\tPROCESS"
+	}
+	fmt.Fprintln(tw, header)
+
+	for _, assoc := range assocs {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%d\t%s\t%d\t%s\t%d\t%d\t%d\t%s\t%d",
+			assoc.AssocId,
+			assoc.St,
+			assoc.Sty,
+			assoc.LPort,
+			joinAddrs(assoc.LAddrs, assoc.PrimaryLAddr),
+			assoc.RPort,
+			joinAddrs(assoc.RAddrs, assoc.PrimaryRAddr),
+			assoc.TxQueue,
+			assoc.RxQueue,
+			assoc.Rtxc,
+			formatUser(assoc.Uid, assoc.UserName),
+			assoc.Inode,
+		)
+		if withProcesses {
+			fmt.Fprintf(tw, "\t%s", formatProcesses(assoc.Owners))
+		}
+		fmt.Fprintln(tw)
+	}
+
+	return tw.Flush()
+}
+
+func writeEPS(w io.Writer, epses []*parser.EPS, withProcesses bool) error {
+	tw := newTableWriter(w)
+
+	header := "LPORT\tSTATE\tTYPE\tLADDRS\tUID\tINODE"
+	if withProcesses {
+		header += "\tPROCESS"
+	}
+	fmt.Fprintln(tw, header)
+
+	for _, eps := range epses {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%d",
+			eps.LPort,
+			eps.Sst,
+			eps.Sty,
+			strings.Join(eps.LAddrs, ","),
+			formatUser(eps.Uid, eps.UserName),
+			eps.Inode,
+		)
+		if withProcesses {
+			fmt.Fprintf(tw, "\t%s", formatProcesses(eps.Owners))
+		}
+		fmt.Fprintln(tw)
+	}
+
+	return tw.Flush()
+}
+
+// writePaths writes a path per line. The primary path is marked with `*`.
+// RTO is converted from jiffies into milliseconds with the kernel's CONFIG_HZ.
+func writePaths(w io.Writer, views []*parser.AssociationView, hz int) error {
+	tw := newTableWriter(w)
+
+	fmt.Fprintln(tw, "ASSOC-ID\tLPORT\tRPORT\tADDR\tSTATE\tRTO(ms)\tHB\tRTX")
+	for _, view := range views {
+		for _, path := range view.Paths {
+			addr := path.Addr
+			if addr == view.Assoc.PrimaryRAddr {
+				addr = "*" + addr
+			}
+
+			hb := "off"
+			if path.HbAct != 0 {
+				hb = "on"
+			}
+
+			fmt.Fprintf(tw, "%d\t%d\t%d\t%s\t%s\t%d\t%s\t%d\n",
+				view.Assoc.AssocId,
+				view.Assoc.LPort,
+				view.Assoc.RPort,
+				addr,
+				path.State,
+				parser.JiffiesToDuration(path.RTO, hz).Milliseconds(),
+				hb,
+				path.RemAddrRtx,
+			)
+		}
+	}
+
+	return tw.Flush()
+}
+
+// joinAddrs joins the addresses with commas. The primary address is marked with `*`.
+func joinAddrs(addrs []string, primary string) string {
+	marked := make([]string, len(addrs))
+	for i, addr := range addrs {
+		if addr == primary {
+			addr = "*" + addr
+		}
+		marked[i] = addr
+	}
+	return strings.Join(marked, ",")
+}
+
+func formatUser(uid uint64, name string) string {
+	if name != "" {
+		return name
+	}
+	return strconv.FormatUint(uid, 10)
+}
+
+// formatProcesses formats the processes like ss(8); e.g. `"sshd",pid=123`.
+func formatProcesses(processes []*parser.Process) string {
+	formatted := make([]string, len(processes))
+	for i, process := range processes {
+		formatted[i] = fmt.Sprintf("%q,pid=%d", process.Comm, process.PID)
+	}
+	return strings.Join(formatted, " ")
+}
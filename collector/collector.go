@@ -0,0 +1,358 @@
+// Package collector provides a prometheus.Collector that exposes the SCTP metrics of a parser.Source; e.g. the proc filesystem.
+package collector
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	parser "github.com/moznion/go-sctp-proc-parser"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "sctp"
+
+// Cardinality is the granularity of the labels of the association, path and endpoint metrics.
+type Cardinality int
+
+const (
+	// ByPeer labels the metrics with the local port, the remote port and the remote address.
+	// The association metrics are labelled with the primary remote address.
+	ByPeer Cardinality = iota
+	// ByPort labels the metrics with the local port only. The values of the associations on the same port are summed up,
+	// except the RTO of the paths (`sctp_path_rto_seconds`) that is the maximum among them.
+	ByPort
+	// Aggregated doesn't label the metrics. The values of all associations are summed up,
+	// except the RTO of the paths (`sctp_path_rto_seconds`) that is the maximum among them.
+	Aggregated
+)
+
+// labelNames returns the names of the labels that identify an association or a path on the cardinality.
+func (c Cardinality) labelNames() []string {
+	switch c {
+	case ByPeer:
+		return []string{"local_port", "remote_port", "remote_addr"}
+	case ByPort:
+		return []string{"local_port"}
+	default:
+		return nil
+	}
+}
+
+func (c Cardinality) labelValues(lport int64, rport int64, raddr string) []string {
+	switch c {
+	case ByPeer:
+		return []string{formatPort(lport), formatPort(rport), raddr}
+	case ByPort:
+		return []string{formatPort(lport)}
+	default:
+		return nil
+	}
+}
+
+func formatPort(port int64) string {
+	return strconv.FormatInt(port, 10)
+}
+
+// assocGauge is a gauge of an association field.
+type assocGauge struct {
+	name  string
+	help  string
+	field parser.AssocField
+	value func(a *parser.Assoc) float64
+}
+
+var assocGauges = []*assocGauge{
+	{"tx_queue_bytes", "The number of bytes in the transmit queue.", parser.AssocFieldTxQueue, func(a *parser.Assoc) float64 { return float64(a.TxQueue) }},
+	{"rx_queue_bytes", "The number of bytes in the receive queue.", parser.AssocFieldRxQueue, func(a *parser.Assoc) float64 { return float64(a.RxQueue) }},
+	{"sndbuf_bytes", "The size of the send buffer.", parser.AssocFieldSndbuf, func(a *parser.Assoc) float64 { return float64(a.Sndbuf) }},
+	{"rcvbuf_bytes", "The size of the receive buffer.", parser.AssocFieldRcvbuf, func(a *parser.Assoc) float64 { return float64(a.Rcvbuf) }},
+	{"wmem_alloc_bytes", "The number of bytes of the allocated write memory (wmema).", parser.AssocFieldWmema, func(a *parser.Assoc) float64 { return float64(a.Wmema) }},
+	{"wmem_queued_bytes", "The number of bytes of the queued write memory (wmemq).", parser.AssocFieldWmemq, func(a *parser.Assoc) float64 { return float64(a.Wmemq) }},
+	{"retransmitted_chunks", "The number of the retransmitted chunks (RTXC).", parser.AssocFieldRtxc, func(a *parser.Assoc) float64 { return float64(a.Rtxc) }},
+	{"t1_expirations", "The number of the expirations of the T1 (init) timer.", parser.AssocFieldT1x, func(a *parser.Assoc) float64 { return float64(a.T1x) }},
+	{"t2_expirations", "The number of the expirations of the T2 (shutdown) timer.", parser.AssocFieldT2x, func(a *parser.Assoc) float64 { return float64(a.T2x) }},
+}
+
+// Collector is a prometheus.Collector that reads the SCTP records from the source on every collection.
+//
+// If SCTP is not available (see parser.ErrSCTPNotAvailable), only `sctp_up` is reported as 0.
+// The other errors, including those of reading the SNMP counters, are reported as invalid metrics of `sctp_up`, which make the scrape fail.
+type Collector struct {
+	// HZ is the kernel's CONFIG_HZ, with which the values in jiffies (e.g. parser.Remaddr.RTO) are converted into seconds.
+	// This is parser.DefaultHZ by default.
+	HZ int
+
+	source      parser.Source
+	cardinality Cardinality
+	maxAttempts int
+
+	upDesc          *prometheus.Desc
+	assocsDesc      *prometheus.Desc
+	assocGaugeDescs []*prometheus.Desc
+	pathsDesc       *prometheus.Desc
+	pathRTODesc     *prometheus.Desc
+	endpointsDesc   *prometheus.Desc
+	snmpDescs       map[string]*prometheus.Desc
+	unknownSNMPDesc *prometheus.Desc
+}
+
+// NewCollector returns a new Collector that reads the records from the source with the labels of the cardinality;
+// e.g. parser.NewProcFS(parser.DefaultProcRoot) for the proc filesystem.
+func NewCollector(source parser.Source, cardinality Cardinality) *Collector {
+	labels := cardinality.labelNames()
+
+	assocGaugeDescs := make([]*prometheus.Desc, len(assocGauges))
+	for i, gauge := range assocGauges {
+		assocGaugeDescs[i] = prometheus.NewDesc(prometheus.BuildFQName(namespace, "association", gauge.name), gauge.help, labels, nil)
+	}
+
+	snmpDescs := make(map[string]*prometheus.Desc)
+	for name := range (&parser.SNMP{}).Counters() {
+		snmpDescs[name] = newSNMPDesc(name)
+	}
+
+	return &Collector{
+		HZ:          parser.DefaultHZ,
+		source:      source,
+		cardinality: cardinality,
+		maxAttempts: parser.DefaultSnapshotAttempts,
+		upDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "up"),
+			"Whether SCTP is available.",
+			nil, nil,
+		),
+		assocsDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "associations"),
+			"The number of the associations by the state.",
+			append(labels, "state"), nil,
+		),
+		assocGaugeDescs: assocGaugeDescs,
+		pathsDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "paths"),
+			"The number of the paths to the remote addresses by the state.",
+			append(labels, "state"), nil,
+		),
+		pathRTODesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "path", "rto_seconds"),
+			"The retransmission timeout of the path; the maximum among the paths unless the cardinality is by peer.",
+			labels, nil,
+		),
+		endpointsDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "endpoints"),
+			"The number of the endpoints.",
+			endpointLabelNames(cardinality), nil,
+		),
+		snmpDescs: snmpDescs,
+		unknownSNMPDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "snmp", "unknown"),
+			"The SCTP MIB counters that this package doesn't know.",
+			[]string{"counter"}, nil,
+		),
+	}
+}
+
+func endpointLabelNames(cardinality Cardinality) []string {
+	if cardinality == Aggregated {
+		return nil
+	}
+	return []string{"local_port"}
+}
+
+// newSNMPDesc returns the desc of the SCTP MIB counter; e.g. `sctp_snmp_out_ctrl_chunks_total` for SctpOutCtrlChunks.
+// SctpCurrEstab is a gauge, so it doesn't have the `_total` suffix.
+func newSNMPDesc(name string) *prometheus.Desc {
+	metricName := snakeCase(strings.TrimPrefix(name, "Sctp"))
+	if !isSNMPGauge(name) {
+		metricName += "_total"
+	}
+	return prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "snmp", metricName),
+		"The SCTP MIB counter "+name+".",
+		nil, nil,
+	)
+}
+
+func isSNMPGauge(name string) bool {
+	return name == "SctpCurrEstab"
+}
+
+// snakeCase converts the CamelCase name into snake_case; e.g. `InSCTPPacks` into `in_sctp_packs`, and `T1InitExpireds` into `t1_init_expireds`.
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.upDesc
+	ch <- c.assocsDesc
+	for _, desc := range c.assocGaugeDescs {
+		ch <- desc
+	}
+	ch <- c.pathsDesc
+	ch <- c.pathRTODesc
+	ch <- c.endpointsDesc
+	for _, desc := range c.snmpDescs {
+		ch <- desc
+	}
+	ch <- c.unknownSNMPDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	ctx := context.Background()
+	snapshot, err := parser.ReadSnapshotFrom(ctx, c.source, c.maxAttempts)
+	if err != nil {
+		c.collectError(ch, err)
+		return
+	}
+	snmp, err := c.source.SNMP(ctx)
+	if err != nil {
+		c.collectError(ch, err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.upDesc, prometheus.GaugeValue, 1)
+
+	c.collectAssociations(ch, snapshot)
+	c.collectEndpoints(ch, snapshot.EPS)
+	c.collectSNMP(ch, snmp)
+}
+
+// collectError reports `sctp_up` as 0 if SCTP is not available, otherwise the error as an invalid metric of `sctp_up`.
+func (c *Collector) collectError(ch chan<- prometheus.Metric, err error) {
+	if errors.Is(err, parser.ErrSCTPNotAvailable) {
+		ch <- prometheus.MustNewConstMetric(c.upDesc, prometheus.GaugeValue, 0)
+		return
+	}
+	ch <- prometheus.NewInvalidMetric(c.upDesc, err)
+}
+
+func (c *Collector) collectAssociations(ch chan<- prometheus.Metric, snapshot *parser.Snapshot) {
+	assocs := newSamples()
+	gauges := make([]*samples, len(assocGauges))
+	for i := range gauges {
+		gauges[i] = newSamples()
+	}
+	paths := newSamples()
+	rtos := newSamples()
+
+	for _, view := range snapshot.Associations {
+		assoc := view.Assoc
+		labels := c.cardinality.labelValues(assoc.LPort, assoc.RPort, primaryRAddr(assoc))
+
+		assocs.add(append(labels, assoc.St.String()), 1)
+		for i, gauge := range assocGauges {
+			if assoc.Has(gauge.field) {
+				gauges[i].add(labels, gauge.value(assoc))
+			}
+		}
+
+		for _, path := range view.Paths {
+			labels := c.cardinality.labelValues(assoc.LPort, assoc.RPort, path.Addr)
+			paths.add(append(labels, path.State.String()), 1)
+			rtos.max(labels, parser.JiffiesToDuration(path.RTO, c.HZ).Seconds())
+		}
+	}
+
+	assocs.collect(ch, c.assocsDesc)
+	for i, desc := range c.assocGaugeDescs {
+		gauges[i].collect(ch, desc)
+	}
+	paths.collect(ch, c.pathsDesc)
+	rtos.collect(ch, c.pathRTODesc)
+}
+
+func (c *Collector) collectEndpoints(ch chan<- prometheus.Metric, epses []*parser.EPS) {
+	endpoints := newSamples()
+	for _, eps := range epses {
+		if c.cardinality == Aggregated {
+			endpoints.add(nil, 1)
+		} else {
+			endpoints.add([]string{formatPort(eps.LPort)}, 1)
+		}
+	}
+	endpoints.collect(ch, c.endpointsDesc)
+}
+
+func (c *Collector) collectSNMP(ch chan<- prometheus.Metric, snmp *parser.SNMP) {
+	for name, value := range snmp.Counters() {
+		desc, ok := c.snmpDescs[name]
+		if !ok {
+			ch <- prometheus.MustNewConstMetric(c.unknownSNMPDesc, prometheus.UntypedValue, float64(value), name)
+			continue
+		}
+
+		valueType := prometheus.CounterValue
+		if isSNMPGauge(name) {
+			valueType = prometheus.GaugeValue
+		}
+		ch <- prometheus.MustNewConstMetric(desc, valueType, float64(value))
+	}
+}
+
+// primaryRAddr returns the primary remote address of the association, or the first one if there is no marked address.
+func primaryRAddr(assoc *parser.Assoc) string {
+	if assoc.PrimaryRAddr != "" {
+		return assoc.PrimaryRAddr
+	}
+	if len(assoc.RAddrs) > 0 {
+		return assoc.RAddrs[0]
+	}
+	return ""
+}
+
+// samples sums up (or takes the maximum of) the values by the label values, so that the metrics don't have duplicated label sets.
+type samples struct {
+	keys   []string
+	labels map[string][]string
+	values map[string]float64
+}
+
+func newSamples() *samples {
+	return &samples{
+		labels: make(map[string][]string),
+		values: make(map[string]float64),
+	}
+}
+
+func (s *samples) add(labels []string, value float64) {
+	s.merge(labels, value, func(a float64, b float64) float64 { return a + b })
+}
+
+func (s *samples) max(labels []string, value float64) {
+	s.merge(labels, value, math.Max)
+}
+
+func (s *samples) merge(labels []string, value float64, merge func(a float64, b float64) float64) {
+	key := strings.Join(labels, "\x00")
+	if _, ok := s.labels[key]; !ok {
+		s.keys = append(s.keys, key)
+		s.labels[key] = labels
+		s.values[key] = value
+		return
+	}
+	s.values[key] = merge(s.values[key], value)
+}
+
+func (s *samples) collect(ch chan<- prometheus.Metric, desc *prometheus.Desc) {
+	sort.Strings(s.keys)
+	for _, key := range s.keys {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, s.values[key], s.labels[key]...)
+	}
+}
@@ -0,0 +1,253 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	parser "github.com/moznion/go-sctp-proc-parser"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	assocsContents = ` ASSOC     SOCK   STY SST ST HBKT ASSOC-ID TX_QUEUE RX_QUEUE UID INODE LPORT RPORT LADDRS <-> RADDRS HBINT INS OUTS MAXRT T1X T2X RTXC wmema wmemq sndbuf rcvbuf
+       0        0 2   1   3  0      60      100      496       0 188897 3868 54321  10.0.0.1 <-> *10.0.0.2 10.0.0.3 	   30000 65535 65535   10    1    0        4        1        0   212992   212992
+       0        0 2   1   3  0      61       50        0       0 189472 3868 54322  10.0.0.1 <-> *10.0.0.4 	   30000 65535 65535   10    0    2        6        1        0   212992   212992
+`
+	remaddrContents = `ADDR ASSOC_ID HB_ACT RTO MAX_PATH_RTX REM_ADDR_RTX START STATE
+10.0.0.2  60 1 3000 5 0 0 2
+10.0.0.3  60 1 1000 5 0 0 0
+10.0.0.4  61 1 3000 5 0 0 2
+`
+	epsContents = ` ENDPT     SOCK   STY SST HBKT LPORT   UID INODE LADDRS
+       0        0 2   10  24   3868     0 227065 10.0.0.1
+`
+	snmpContents = `SctpCurrEstab                   	2
+SctpT3RtxExpireds               	7
+SctpNewCounter                  	42
+`
+)
+
+func writeProcFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, contents := range files {
+		path := filepath.Join(root, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.NoError(t, os.WriteFile(path, []byte(contents), 0o644))
+	}
+}
+
+func newTestCollector(t *testing.T, cardinality Cardinality) *Collector {
+	root := t.TempDir()
+	writeProcFiles(t, root, map[string]string{
+		"net/sctp/assocs":  assocsContents,
+		"net/sctp/remaddr": remaddrContents,
+		"net/sctp/eps":     epsContents,
+		"net/sctp/snmp":    snmpContents,
+	})
+	return NewCollector(parser.NewProcFS(root), cardinality)
+}
+
+func TestCollector_ByPeer(t *testing.T) {
+	c := newTestCollector(t, ByPeer)
+
+	expected := `
+# HELP sctp_association_tx_queue_bytes The number of bytes in the transmit queue.
+# TYPE sctp_association_tx_queue_bytes gauge
+sctp_association_tx_queue_bytes{local_port="3868",remote_addr="10.0.0.2",remote_port="54321"} 100
+sctp_association_tx_queue_bytes{local_port="3868",remote_addr="10.0.0.4",remote_port="54322"} 50
+# HELP sctp_association_retransmitted_chunks The number of the retransmitted chunks (RTXC).
+# TYPE sctp_association_retransmitted_chunks gauge
+sctp_association_retransmitted_chunks{local_port="3868",remote_addr="10.0.0.2",remote_port="54321"} 4
+sctp_association_retransmitted_chunks{local_port="3868",remote_addr="10.0.0.4",remote_port="54322"} 6
+# HELP sctp_associations The number of the associations by the state.
+# TYPE sctp_associations gauge
+sctp_associations{local_port="3868",remote_addr="10.0.0.2",remote_port="54321",state="ESTABLISHED"} 1
+sctp_associations{local_port="3868",remote_addr="10.0.0.4",remote_port="54322",state="ESTABLISHED"} 1
+# HELP sctp_path_rto_seconds The retransmission timeout of the path; the maximum among the paths unless the cardinality is by peer.
+# TYPE sctp_path_rto_seconds gauge
+sctp_path_rto_seconds{local_port="3868",remote_addr="10.0.0.2",remote_port="54321"} 3
+sctp_path_rto_seconds{local_port="3868",remote_addr="10.0.0.3",remote_port="54321"} 1
+sctp_path_rto_seconds{local_port="3868",remote_addr="10.0.0.4",remote_port="54322"} 3
+# HELP sctp_paths The number of the paths to the remote addresses by the state.
+# TYPE sctp_paths gauge
+sctp_paths{local_port="3868",remote_addr="10.0.0.2",remote_port="54321",state="ACTIVE"} 1
+sctp_paths{local_port="3868",remote_addr="10.0.0.3",remote_port="54321",state="INACTIVE"} 1
+sctp_paths{local_port="3868",remote_addr="10.0.0.4",remote_port="54322",state="ACTIVE"} 1
+# HELP sctp_endpoints The number of the endpoints.
+# TYPE sctp_endpoints gauge
+sctp_endpoints{local_port="3868"} 1
+# HELP sctp_up Whether SCTP is available.
+# TYPE sctp_up gauge
+sctp_up 1
+`
+	err := testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"sctp_association_tx_queue_bytes",
+		"sctp_association_retransmitted_chunks",
+		"sctp_associations",
+		"sctp_path_rto_seconds",
+		"sctp_paths",
+		"sctp_endpoints",
+		"sctp_up",
+	)
+	assert.NoError(t, err)
+}
+
+func TestCollector_ByPort(t *testing.T) {
+	c := newTestCollector(t, ByPort)
+
+	expected := `
+# HELP sctp_association_tx_queue_bytes The number of bytes in the transmit queue.
+# TYPE sctp_association_tx_queue_bytes gauge
+sctp_association_tx_queue_bytes{local_port="3868"} 150
+# HELP sctp_associations The number of the associations by the state.
+# TYPE sctp_associations gauge
+sctp_associations{local_port="3868",state="ESTABLISHED"} 2
+# HELP sctp_paths The number of the paths to the remote addresses by the state.
+# TYPE sctp_paths gauge
+sctp_paths{local_port="3868",state="ACTIVE"} 2
+sctp_paths{local_port="3868",state="INACTIVE"} 1
+# HELP sctp_path_rto_seconds The retransmission timeout of the path; the maximum among the paths unless the cardinality is by peer.
+# TYPE sctp_path_rto_seconds gauge
+sctp_path_rto_seconds{local_port="3868"} 3
+`
+	err := testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"sctp_association_tx_queue_bytes",
+		"sctp_associations",
+		"sctp_paths",
+		"sctp_path_rto_seconds",
+	)
+	assert.NoError(t, err)
+}
+
+func TestCollector_Aggregated(t *testing.T) {
+	c := newTestCollector(t, Aggregated)
+
+	expected := `
+# HELP sctp_association_t1_expirations The number of the expirations of the T1 (init) timer.
+# TYPE sctp_association_t1_expirations gauge
+sctp_association_t1_expirations 1
+# HELP sctp_endpoints The number of the endpoints.
+# TYPE sctp_endpoints gauge
+sctp_endpoints 1
+# HELP sctp_path_rto_seconds The retransmission timeout of the path; the maximum among the paths unless the cardinality is by peer.
+# TYPE sctp_path_rto_seconds gauge
+sctp_path_rto_seconds 3
+# HELP sctp_snmp_curr_estab The SCTP MIB counter SctpCurrEstab.
+# TYPE sctp_snmp_curr_estab gauge
+sctp_snmp_curr_estab 2
+# HELP sctp_snmp_t3_rtx_expireds_total The SCTP MIB counter SctpT3RtxExpireds.
+# TYPE sctp_snmp_t3_rtx_expireds_total counter
+sctp_snmp_t3_rtx_expireds_total 7
+# HELP sctp_snmp_unknown The SCTP MIB counters that this package doesn't know.
+# TYPE sctp_snmp_unknown untyped
+sctp_snmp_unknown{counter="SctpNewCounter"} 42
+`
+	err := testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"sctp_association_t1_expirations",
+		"sctp_endpoints",
+		"sctp_path_rto_seconds",
+		"sctp_snmp_curr_estab",
+		"sctp_snmp_t3_rtx_expireds_total",
+		"sctp_snmp_unknown",
+	)
+	assert.NoError(t, err)
+}
+
+func TestSnakeCase(t *testing.T) {
+	for name, expected := range map[string]string{
+		"CurrEstab":               "curr_estab",
+		"OutCtrlChunks":           "out_ctrl_chunks",
+		"InSCTPPacks":             "in_sctp_packs",
+		"T1InitExpireds":          "t1_init_expireds",
+		"T5ShutdownGuardExpireds": "t5_shutdown_guard_expireds",
+	} {
+		assert.Equal(t, expected, snakeCase(name))
+	}
+}
+
+func TestCollector_WithoutSCTP(t *testing.T) {
+	c := NewCollector(parser.NewProcFS(t.TempDir()), ByPeer)
+
+	expected := `
+# HELP sctp_up Whether SCTP is available.
+# TYPE sctp_up gauge
+sctp_up 0
+`
+	assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected)))
+}
+
+// snmpErrorSource fails to read the SNMP counters.
+type snmpErrorSource struct {
+	*parser.MemorySource
+	err error
+}
+
+func (s *snmpErrorSource) SNMP(ctx context.Context) (*parser.SNMP, error) {
+	return nil, s.err
+}
+
+func TestCollector_SNMPError(t *testing.T) {
+	snmpErr := errors.New("snmp error")
+	c := NewCollector(&snmpErrorSource{MemorySource: parser.NewMemorySource(nil, nil, nil, nil), err: snmpErr}, ByPeer)
+
+	registry := prometheus.NewPedanticRegistry()
+	assert.NoError(t, registry.Register(c))
+	_, err := registry.Gather()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "snmp error")
+	assert.Contains(t, err.Error(), "sctp_up")
+}
+
+func TestCollector_Register(t *testing.T) {
+	registry := prometheus.NewPedanticRegistry()
+	assert.NoError(t, registry.Register(newTestCollector(t, ByPeer)))
+
+	_, err := registry.Gather()
+	assert.NoError(t, err)
+}
+
+func TestCollector_WithMemorySource(t *testing.T) {
+	source := parser.NewMemorySource([]*parser.Assoc{
+		{AssocId: 1, St: parser.AssocStateEstablished, LPort: 2905, RPort: 40000, RAddrs: []string{"10.0.0.2"}},
+		{AssocId: 2, St: parser.AssocStateShutdownSent, LPort: 2905, RPort: 40001, RAddrs: []string{"10.0.0.3"}},
+	}, nil, []*parser.Remaddr{
+		{AssocID: 1, Addr: "10.0.0.2", State: parser.TransportStateActive},
+		{AssocID: 2, Addr: "10.0.0.3", State: parser.TransportStateInactive},
+	}, &parser.SNMP{CurrEstab: 1})
+	c := NewCollector(source, Aggregated)
+
+	expected := `
+# HELP sctp_associations The number of the associations by the state.
+# TYPE sctp_associations gauge
+sctp_associations{state="ESTABLISHED"} 1
+sctp_associations{state="SHUTDOWN_SENT"} 1
+# HELP sctp_paths The number of the paths to the remote addresses by the state.
+# TYPE sctp_paths gauge
+sctp_paths{state="ACTIVE"} 1
+sctp_paths{state="INACTIVE"} 1
+# HELP sctp_snmp_curr_estab The SCTP MIB counter SctpCurrEstab.
+# TYPE sctp_snmp_curr_estab gauge
+sctp_snmp_curr_estab 1
+`
+	assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected), "sctp_associations", "sctp_paths", "sctp_snmp_curr_estab"))
+}
+
+func TestCollector_HZ(t *testing.T) {
+	c := newTestCollector(t, ByPeer)
+	c.HZ = 250
+
+	expected := `
+# HELP sctp_path_rto_seconds The retransmission timeout of the path; the maximum among the paths unless the cardinality is by peer.
+# TYPE sctp_path_rto_seconds gauge
+sctp_path_rto_seconds{local_port="3868",remote_addr="10.0.0.2",remote_port="54321"} 12
+sctp_path_rto_seconds{local_port="3868",remote_addr="10.0.0.3",remote_port="54321"} 4
+sctp_path_rto_seconds{local_port="3868",remote_addr="10.0.0.4",remote_port="54322"} 12
+`
+	assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected), "sctp_path_rto_seconds"))
+}
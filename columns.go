@@ -0,0 +1,66 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+)
+
+// columnLayout describes the order of the columns on a line of SCTP proc contents.
+//
+// The address lists (e.g. `LADDRS <-> RADDRS`) have variable length, so the columns before them are
+// located from the beginning of a line and the columns after them are located from the end of a line.
+type columnLayout struct {
+	leading  []string
+	trailing []string
+}
+
+// parseColumnLayout builds a columnLayout from a header line.
+// addrColumns are the header names of the variable-length address lists; it returns false if the header doesn't have them.
+func parseColumnLayout(header string, addrColumns []string) (*columnLayout, bool) {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return nil, false
+	}
+
+	names := spacesRe.Split(header, -1)
+	if len(addrColumns) <= 0 {
+		return &columnLayout{leading: names}, true
+	}
+
+	for i := 0; i+len(addrColumns) <= len(names); i++ {
+		if equalStrings(names[i:i+len(addrColumns)], addrColumns) {
+			return &columnLayout{
+				leading:  names[:i],
+				trailing: names[i+len(addrColumns):],
+			}, true
+		}
+	}
+	return nil, false
+}
+
+// mustParseColumnLayout is like parseColumnLayout but panics if the header is invalid.
+func mustParseColumnLayout(header string, addrColumns []string) *columnLayout {
+	layout, ok := parseColumnLayout(header, addrColumns)
+	if !ok {
+		panic("invalid header: " + header)
+	}
+	return layout
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// parseIntColumn parses a decimal column value into an int64-based type such as AssocState.
+func parseIntColumn[T ~int64](v string) (T, error) {
+	n, err := strconv.ParseInt(v, 10, 64)
+	return T(n), err
+}
@@ -0,0 +1,139 @@
+package parser
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Container runtimes that ParseCgroup detects.
+const (
+	RuntimeDocker     = "docker"
+	RuntimeContainerd = "containerd"
+	RuntimeCRIO       = "cri-o"
+)
+
+// containerRuntimes maps the prefixes of the container cgroups to the runtimes.
+var containerRuntimes = map[string]string{
+	"docker":         RuntimeDocker,
+	"cri-containerd": RuntimeContainerd,
+	"crio":           RuntimeCRIO,
+}
+
+// Container is a container that a process belongs to.
+type Container struct {
+	// ID is the full ID of the container.
+	ID string `json:"id" yaml:"id"`
+	// Runtime is the container runtime; e.g. RuntimeDocker. It is empty if the cgroup doesn't tell it.
+	Runtime string `json:"runtime,omitempty" yaml:"runtime,omitempty"`
+	// PodUID is the UID of the Kubernetes pod that the container belongs to. It is empty if the container is not of a pod.
+	PodUID string `json:"pod_uid,omitempty" yaml:"pod_uid,omitempty"`
+}
+
+// ParseCgroup parses the contents of `/proc/<pid>/cgroup` and returns the container of the process.
+// It returns nil if the process doesn't belong to any container.
+//
+// It recognizes the cgroup paths of docker, containerd and cri-o, managed by either cgroupfs or systemd, e.g.:
+//
+//	0::/system.slice/docker-<id>.scope
+//	0::/kubepods/besteffort/pod<uid>/<id>
+//	0::/kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod<uid>.slice/cri-containerd-<id>.scope
+//	0::/kubepods.slice/kubepods-pod<uid>.slice/crio-<id>.scope
+func ParseCgroup(contents string) *Container {
+	for _, line := range strings.Split(contents, "\n") {
+		// hierarchy-ID:controller-list:cgroup-path
+		fields := strings.SplitN(line, ":", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		if container := parseCgroupPath(fields[2]); container != nil {
+			return container
+		}
+	}
+	return nil
+}
+
+func parseCgroupPath(path string) *Container {
+	var container *Container
+	podUID := ""
+	parent := ""
+	for _, segment := range strings.Split(path, "/") {
+		if m := podRe.FindStringSubmatch(segment); m != nil {
+			podUID = strings.ReplaceAll(m[1], "_", "-")
+		} else if m := containerScopeRe.FindStringSubmatch(segment); m != nil {
+			container = &Container{ID: m[2], Runtime: containerRuntimes[m[1]]}
+		} else if containerIDRe.MatchString(segment) {
+			container = &Container{ID: segment, Runtime: containerRuntimes[parent]}
+		}
+		parent = segment
+	}
+
+	if container == nil {
+		return nil
+	}
+	container.PodUID = podUID
+	return container
+}
+
+// ReadContainer reads `<pid>/cgroup` and returns the container of the process. It returns nil if the process doesn't belong to any container.
+func (fs ProcFS) ReadContainer(pid int) (*Container, error) {
+	contents, err := os.ReadFile(fs.Path(strconv.Itoa(pid), "cgroup"))
+	if err != nil {
+		return nil, err
+	}
+	return ParseCgroup(string(contents)), nil
+}
+
+// ReadNetNSContainer returns the container of the network namespace; that is the container of the first process in the namespace
+// that belongs to a container. The processes whose cgroup can't be read are skipped.
+// It returns nil if no processes belong to any container, and for the host's namespace (i.e. the one of PID 1)
+// even if containers share it; e.g. with hostNetwork or `--net=host`.
+//
+// Note that the containers of a Kubernetes pod share the namespace, so this returns the first one of them (usually the sandbox)
+// and Container.PodUID is what identifies the namespace.
+func (fs ProcFS) ReadNetNSContainer(ns *NetNS) *Container {
+	if fs.isHostNetNS(ns) {
+		return nil
+	}
+	for _, pid := range ns.PIDs {
+		container, err := fs.ReadContainer(pid)
+		if err != nil {
+			continue
+		}
+		if container != nil {
+			return container
+		}
+	}
+	return nil
+}
+
+// isHostNetNS returns whether the namespace is the one of PID 1.
+func (fs ProcFS) isHostNetNS(ns *NetNS) bool {
+	if len(ns.PIDs) > 0 && ns.PIDs[0] == 1 { // PIDs are in ascending order
+		return true
+	}
+	inode, ok := fs.readNetNSInode(1)
+	return ok && inode == ns.Inode
+}
+
+// AnnotateAssocs sets the container to Assoc.Container.
+func (c *Container) AnnotateAssocs(assocs []*Assoc) {
+	for _, assoc := range assocs {
+		assoc.Container = c
+	}
+}
+
+// AnnotateEPS sets the container to EPS.Container.
+func (c *Container) AnnotateEPS(eps []*EPS) {
+	for _, e := range eps {
+		e.Container = c
+	}
+}
+
+// AnnotateSnapshot sets the container to the associations and the endpoints of the snapshot.
+func (c *Container) AnnotateSnapshot(snapshot *Snapshot) {
+	for _, view := range snapshot.Associations {
+		view.Assoc.Container = c
+	}
+	c.AnnotateEPS(snapshot.EPS)
+}
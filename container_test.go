@@ -0,0 +1,129 @@
+package parser
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testContainerID = "4f8d1b2c3a4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8"
+
+func TestParseCgroup(t *testing.T) {
+	testCases := []struct {
+		name     string
+		contents string
+		expected *Container
+	}{
+		{
+			name:     "docker with cgroupfs",
+			contents: "12:pids:/docker/" + testContainerID + "\n11:memory:/docker/" + testContainerID + "\n",
+			expected: &Container{ID: testContainerID, Runtime: RuntimeDocker},
+		},
+		{
+			name:     "docker with systemd",
+			contents: "0::/system.slice/docker-" + testContainerID + ".scope\n",
+			expected: &Container{ID: testContainerID, Runtime: RuntimeDocker},
+		},
+		{
+			name:     "kubernetes with cgroupfs",
+			contents: "0::/kubepods/besteffort/pod8e5f3a1c-2b4d-4c6e-8f0a-1b2c3d4e5f60/" + testContainerID + "\n",
+			expected: &Container{ID: testContainerID, PodUID: "8e5f3a1c-2b4d-4c6e-8f0a-1b2c3d4e5f60"},
+		},
+		{
+			name:     "containerd with systemd",
+			contents: "0::/kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod8e5f3a1c_2b4d_4c6e_8f0a_1b2c3d4e5f60.slice/cri-containerd-" + testContainerID + ".scope\n",
+			expected: &Container{ID: testContainerID, Runtime: RuntimeContainerd, PodUID: "8e5f3a1c-2b4d-4c6e-8f0a-1b2c3d4e5f60"},
+		},
+		{
+			name:     "cri-o with systemd",
+			contents: "0::/kubepods.slice/kubepods-pod8e5f3a1c_2b4d_4c6e_8f0a_1b2c3d4e5f60.slice/crio-" + testContainerID + ".scope\n",
+			expected: &Container{ID: testContainerID, Runtime: RuntimeCRIO, PodUID: "8e5f3a1c-2b4d-4c6e-8f0a-1b2c3d4e5f60"},
+		},
+		{
+			name:     "cri-o with cgroupfs",
+			contents: "0::/kubepods/burstable/pod8e5f3a1c-2b4d-4c6e-8f0a-1b2c3d4e5f60/crio-" + testContainerID + "\n",
+			expected: &Container{ID: testContainerID, Runtime: RuntimeCRIO, PodUID: "8e5f3a1c-2b4d-4c6e-8f0a-1b2c3d4e5f60"},
+		},
+		{
+			name:     "conmon of cri-o",
+			contents: "0::/kubepods.slice/kubepods-pod8e5f3a1c_2b4d_4c6e_8f0a_1b2c3d4e5f60.slice/crio-conmon-" + testContainerID + ".scope\n",
+			expected: nil,
+		},
+		{
+			name:     "host process",
+			contents: "0::/system.slice/sshd.service\n",
+			expected: nil,
+		},
+		{
+			name:     "empty",
+			contents: "",
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ParseCgroup(tc.contents))
+		})
+	}
+}
+
+func TestProcFS_ReadNetNSContainer(t *testing.T) {
+	root := t.TempDir()
+	makeFakeNetNS(t, root, "1", "4026531840", nil, nil)
+	makeFakeNetNS(t, root, "20", "4026532000", nil, nil)
+	makeFakeNetNS(t, root, "21", "4026532000", nil, nil)
+	writeProcFiles(t, root, map[string]string{
+		filepath.Join("1", "cgroup"):  "0::/init.scope\n",
+		filepath.Join("21", "cgroup"): "0::/kubepods.slice/kubepods-pod8e5f3a1c_2b4d_4c6e_8f0a_1b2c3d4e5f60.slice/cri-containerd-" + testContainerID + ".scope\n",
+		// 20 has no cgroup file; e.g. it has exited
+	})
+
+	fs := NewProcFS(root)
+	namespaces, err := fs.ReadNetNamespaces()
+	assert.NoError(t, err)
+	assert.Len(t, namespaces, 2)
+
+	assert.Nil(t, fs.ReadNetNSContainer(namespaces[0]))
+
+	container := fs.ReadNetNSContainer(namespaces[1])
+	assert.Equal(t, &Container{ID: testContainerID, Runtime: RuntimeContainerd, PodUID: "8e5f3a1c-2b4d-4c6e-8f0a-1b2c3d4e5f60"}, container)
+
+	_, err = fs.ReadContainer(20)
+	assert.Error(t, err)
+}
+
+func TestProcFS_ReadNetNSContainer_HostNetwork(t *testing.T) {
+	root := t.TempDir()
+	makeFakeNetNS(t, root, "1", "4026531840", nil, nil)
+	makeFakeNetNS(t, root, "30", "4026531840", nil, nil)
+	writeProcFiles(t, root, map[string]string{
+		filepath.Join("1", "cgroup"):  "0::/init.scope\n",
+		filepath.Join("30", "cgroup"): "0::/system.slice/docker-" + testContainerID + ".scope\n",
+	})
+
+	fs := NewProcFS(root)
+	namespaces, err := fs.ReadNetNamespaces()
+	assert.NoError(t, err)
+	assert.Len(t, namespaces, 1)
+	assert.Nil(t, fs.ReadNetNSContainer(namespaces[0]))
+
+	// the namespace is told by the inode even if PID 1 is not listed; e.g. the PIDs have been filtered
+	assert.Nil(t, fs.ReadNetNSContainer(&NetNS{Inode: 4026531840, PIDs: []int{30}}))
+	container, err := fs.ReadContainer(30)
+	assert.NoError(t, err)
+	assert.NotNil(t, container)
+}
+
+func TestContainer_AnnotateSnapshot(t *testing.T) {
+	container := &Container{ID: testContainerID, Runtime: RuntimeDocker}
+	snapshot := NewSnapshot([]*Assoc{{AssocId: 1}, {AssocId: 2}}, nil)
+	snapshot.EPS = []*EPS{{LPort: 3868}}
+
+	container.AnnotateSnapshot(snapshot)
+
+	assert.Same(t, container, snapshot.Associations[0].Assoc.Container)
+	assert.Same(t, container, snapshot.Associations[1].Assoc.Container)
+	assert.Same(t, container, snapshot.EPS[0].Container)
+}
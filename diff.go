@@ -0,0 +1,176 @@
+package parser
+
+// SnapshotDiff is the difference between two snapshots.
+type SnapshotDiff struct {
+	// Established are the associations that are only in the newer snapshot.
+	Established []*AssociationView
+	// Closed are the associations that are only in the older snapshot.
+	Closed []*AssociationView
+	// StateChanges are the associations whose states have changed.
+	StateChanges []*AssocStateChange
+	// PrimaryPathChanges are the associations whose primary remote addresses have changed.
+	PrimaryPathChanges []*PrimaryPathChange
+	// PathStateChanges are the paths whose states have changed.
+	PathStateChanges []*PathStateChange
+	// AddedRAddrs are the remote addresses that have been added to the associations (e.g. by ADD-IP).
+	AddedRAddrs []*RAddrChange
+	// RemovedRAddrs are the remote addresses that have been removed from the associations (e.g. by ADD-IP).
+	RemovedRAddrs []*RAddrChange
+	// CounterDeltas are the associations whose retransmission counters have changed.
+	CounterDeltas []*CounterDelta
+}
+
+// AssocStateChange represents a change of the state of an association.
+type AssocStateChange struct {
+	AssocID int64
+	Old     AssocState
+	New     AssocState
+}
+
+// PrimaryPathChange represents a change of the primary remote address of an association.
+type PrimaryPathChange struct {
+	AssocID int64
+	Old     string
+	New     string
+}
+
+// PathStateChange represents a change of the state of a path to a remote address of an association.
+type PathStateChange struct {
+	AssocID int64
+	Addr    string
+	Old     TransportState
+	New     TransportState
+}
+
+// RAddrChange represents a remote address that has been added to or removed from an association.
+type RAddrChange struct {
+	AssocID int64
+	Addr    string
+}
+
+// CounterDelta represents the differences of the retransmission counters of an association.
+// The deltas can be negative when the kernel resets the counters; e.g. T1x is reset when the association is established.
+type CounterDelta struct {
+	AssocID int64
+	Rtxc    int64
+	T1x     int64
+	T2x     int64
+}
+
+// IsEmpty returns whether there is no difference.
+func (d *SnapshotDiff) IsEmpty() bool {
+	return len(d.Established) <= 0 &&
+		len(d.Closed) <= 0 &&
+		len(d.StateChanges) <= 0 &&
+		len(d.PrimaryPathChanges) <= 0 &&
+		len(d.PathStateChanges) <= 0 &&
+		len(d.AddedRAddrs) <= 0 &&
+		len(d.RemovedRAddrs) <= 0 &&
+		len(d.CounterDeltas) <= 0
+}
+
+// DiffSnapshots compares the older snapshot with the newer one.
+//
+// Associations are identified by the association ID and the local and remote ports;
+// if an association ID is reused by another association, the older one is reported as closed and the newer one as established.
+// The results are in the order of the associations in the newer snapshot, except Closed that is in the order of the older one.
+func DiffSnapshots(older *Snapshot, newer *Snapshot) *SnapshotDiff {
+	diff := &SnapshotDiff{
+		Established:        make([]*AssociationView, 0),
+		Closed:             make([]*AssociationView, 0),
+		StateChanges:       make([]*AssocStateChange, 0),
+		PrimaryPathChanges: make([]*PrimaryPathChange, 0),
+		PathStateChanges:   make([]*PathStateChange, 0),
+		AddedRAddrs:        make([]*RAddrChange, 0),
+		RemovedRAddrs:      make([]*RAddrChange, 0),
+		CounterDeltas:      make([]*CounterDelta, 0),
+	}
+
+	for _, oldView := range older.Associations {
+		newView, ok := newer.Lookup(oldView.Assoc.AssocId)
+		if !ok || !isSameAssociation(oldView.Assoc, newView.Assoc) {
+			diff.Closed = append(diff.Closed, oldView)
+		}
+	}
+
+	for _, newView := range newer.Associations {
+		newAssoc := newView.Assoc
+		oldView, ok := older.Lookup(newAssoc.AssocId)
+		if !ok || !isSameAssociation(oldView.Assoc, newAssoc) {
+			diff.Established = append(diff.Established, newView)
+			continue
+		}
+		oldAssoc := oldView.Assoc
+		assocID := newAssoc.AssocId
+
+		if oldAssoc.St != newAssoc.St {
+			diff.StateChanges = append(diff.StateChanges, &AssocStateChange{
+				AssocID: assocID,
+				Old:     oldAssoc.St,
+				New:     newAssoc.St,
+			})
+		}
+
+		if oldAssoc.PrimaryRAddr != newAssoc.PrimaryRAddr {
+			diff.PrimaryPathChanges = append(diff.PrimaryPathChanges, &PrimaryPathChange{
+				AssocID: assocID,
+				Old:     oldAssoc.PrimaryRAddr,
+				New:     newAssoc.PrimaryRAddr,
+			})
+		}
+
+		for _, newPath := range newView.Paths {
+			oldPath := oldView.Path(newPath.Addr)
+			if oldPath == nil || oldPath.State == newPath.State {
+				continue
+			}
+			diff.PathStateChanges = append(diff.PathStateChanges, &PathStateChange{
+				AssocID: assocID,
+				Addr:    newPath.Addr,
+				Old:     oldPath.State,
+				New:     newPath.State,
+			})
+		}
+
+		for _, addr := range subtractAddrs(newAssoc.RAddrs, oldAssoc.RAddrs) {
+			diff.AddedRAddrs = append(diff.AddedRAddrs, &RAddrChange{AssocID: assocID, Addr: addr})
+		}
+		for _, addr := range subtractAddrs(oldAssoc.RAddrs, newAssoc.RAddrs) {
+			diff.RemovedRAddrs = append(diff.RemovedRAddrs, &RAddrChange{AssocID: assocID, Addr: addr})
+		}
+
+		delta := &CounterDelta{
+			AssocID: assocID,
+			Rtxc:    newAssoc.Rtxc - oldAssoc.Rtxc,
+			T1x:     newAssoc.T1x - oldAssoc.T1x,
+			T2x:     newAssoc.T2x - oldAssoc.T2x,
+		}
+		if delta.Rtxc != 0 || delta.T1x != 0 || delta.T2x != 0 {
+			diff.CounterDeltas = append(diff.CounterDeltas, delta)
+		}
+	}
+
+	return diff
+}
+
+func isSameAssociation(a *Assoc, b *Assoc) bool {
+	return a.AssocId == b.AssocId && a.LPort == b.LPort && a.RPort == b.RPort
+}
+
+// subtractAddrs returns the addresses that are in a but not in b, in the order of a.
+func subtractAddrs(a []string, b []string) []string {
+	subtracted := make([]string, 0)
+	for _, addr := range a {
+		found := false
+		for _, other := range b {
+			if addr == other {
+				found = true
+				break
+			}
+		}
+		if !found {
+			subtracted = append(subtracted, addr)
+		}
+	}
+	return subtracted
+}
@@ -0,0 +1,57 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	older := NewSnapshot([]*Assoc{
+		{AssocId: 1, LPort: 3868, RPort: 50000, St: AssocStateEstablished, RAddrs: []string{"10.0.0.1", "10.0.0.2"}, PrimaryRAddr: "10.0.0.1", Rtxc: 10, T1x: 1},
+		{AssocId: 2, LPort: 3868, RPort: 50001, St: AssocStateEstablished, RAddrs: []string{"10.0.0.3"}, PrimaryRAddr: "10.0.0.3"},
+		{AssocId: 3, LPort: 3868, RPort: 50002, St: AssocStateEstablished, RAddrs: []string{"10.0.0.4"}, PrimaryRAddr: "10.0.0.4"},
+		{AssocId: 4, LPort: 3868, RPort: 50003, St: AssocStateEstablished, RAddrs: []string{"10.0.0.5"}, PrimaryRAddr: "10.0.0.5"},
+	}, []*Remaddr{
+		{AssocID: 1, Addr: "10.0.0.1", State: TransportStateActive},
+		{AssocID: 1, Addr: "10.0.0.2", State: TransportStateActive},
+	})
+	newer := NewSnapshot([]*Assoc{
+		{AssocId: 1, LPort: 3868, RPort: 50000, St: AssocStateEstablished, RAddrs: []string{"10.0.0.2", "10.0.0.6"}, PrimaryRAddr: "10.0.0.2", Rtxc: 15, T1x: 1, T2x: 2},
+		{AssocId: 2, LPort: 3868, RPort: 50001, St: AssocStateShutdownPending, RAddrs: []string{"10.0.0.3"}, PrimaryRAddr: "10.0.0.3"},
+		{AssocId: 4, LPort: 3868, RPort: 50099, St: AssocStateCookieEchoed, RAddrs: []string{"10.0.0.7"}, PrimaryRAddr: "10.0.0.7"},
+		{AssocId: 5, LPort: 3868, RPort: 50004, St: AssocStateEstablished, RAddrs: []string{"10.0.0.8"}, PrimaryRAddr: "10.0.0.8"},
+	}, []*Remaddr{
+		{AssocID: 1, Addr: "10.0.0.2", State: TransportStatePF},
+		{AssocID: 1, Addr: "10.0.0.6", State: TransportStateUnconfirmed},
+	})
+
+	diff := DiffSnapshots(older, newer)
+
+	assert.False(t, diff.IsEmpty())
+	assert.Equal(t, []*AssociationView{newer.Associations[2], newer.Associations[3]}, diff.Established)
+	assert.Equal(t, []*AssociationView{older.Associations[2], older.Associations[3]}, diff.Closed)
+	assert.Equal(t, []*AssocStateChange{
+		{AssocID: 2, Old: AssocStateEstablished, New: AssocStateShutdownPending},
+	}, diff.StateChanges)
+	assert.Equal(t, []*PrimaryPathChange{
+		{AssocID: 1, Old: "10.0.0.1", New: "10.0.0.2"},
+	}, diff.PrimaryPathChanges)
+	assert.Equal(t, []*PathStateChange{
+		{AssocID: 1, Addr: "10.0.0.2", Old: TransportStateActive, New: TransportStatePF},
+	}, diff.PathStateChanges)
+	assert.Equal(t, []*RAddrChange{{AssocID: 1, Addr: "10.0.0.6"}}, diff.AddedRAddrs)
+	assert.Equal(t, []*RAddrChange{{AssocID: 1, Addr: "10.0.0.1"}}, diff.RemovedRAddrs)
+	assert.Equal(t, []*CounterDelta{
+		{AssocID: 1, Rtxc: 5, T1x: 0, T2x: 2},
+	}, diff.CounterDeltas)
+}
+
+func TestDiffSnapshots_WithoutDifference(t *testing.T) {
+	assocs := []*Assoc{
+		{AssocId: 1, LPort: 3868, RPort: 50000, St: AssocStateEstablished, RAddrs: []string{"10.0.0.1"}, PrimaryRAddr: "10.0.0.1", Rtxc: 10},
+	}
+
+	diff := DiffSnapshots(NewSnapshot(assocs, nil), NewSnapshot(assocs, nil))
+	assert.True(t, diff.IsEmpty())
+}
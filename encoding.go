@@ -0,0 +1,287 @@
+package parser
+
+import (
+	"encoding"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrUnknownEncoding = errors.New("unknown encoding")
+)
+
+// Encoding is a machine-readable format to encode the records in.
+type Encoding string
+
+const (
+	// EncodingJSON encodes the records as a JSON array.
+	EncodingJSON Encoding = "json"
+	// EncodingJSONLines encodes the records as JSON objects, one per line.
+	EncodingJSONLines Encoding = "jsonl"
+	// EncodingCSV encodes the records as CSV with a header line. The lists (e.g. addresses) are joined with spaces in a cell.
+	EncodingCSV Encoding = "csv"
+	// EncodingYAML encodes the records as a YAML sequence.
+	EncodingYAML Encoding = "yaml"
+)
+
+// ParseEncoding returns the Encoding of the name; e.g. `json`. `ndjson` and `yml` are accepted as aliases.
+func ParseEncoding(name string) (Encoding, error) {
+	switch strings.ToLower(name) {
+	case "json":
+		return EncodingJSON, nil
+	case "jsonl", "ndjson":
+		return EncodingJSONLines, nil
+	case "csv":
+		return EncodingCSV, nil
+	case "yaml", "yml":
+		return EncodingYAML, nil
+	default:
+		return "", fmt.Errorf("%q: %w", name, ErrUnknownEncoding)
+	}
+}
+
+// csvColumn is a column of the CSV encoding. The names are the same as the JSON field names.
+type csvColumn[T any] struct {
+	name  string
+	value func(v T) string
+}
+
+var assocCSVColumns = []csvColumn[*Assoc]{
+	{"assoc", assocFieldValue(AssocFieldAssoc, func(a *Assoc) string { return formatUint(a.Assoc) })},
+	{"sock", assocFieldValue(AssocFieldSock, func(a *Assoc) string { return formatUint(a.Sock) })},
+	{"sty", assocFieldValue(AssocFieldSty, func(a *Assoc) string { return formatText(a.Sty) })},
+	{"sst", assocFieldValue(AssocFieldSst, func(a *Assoc) string { return formatText(a.Sst) })},
+	{"st", assocFieldValue(AssocFieldSt, func(a *Assoc) string { return formatText(a.St) })},
+	{"hbkt", assocFieldValue(AssocFieldHbkt, func(a *Assoc) string { return formatInt(a.Hbkt) })},
+	{"assoc_id", assocFieldValue(AssocFieldAssocId, func(a *Assoc) string { return formatInt(a.AssocId) })},
+	{"tx_queue", assocFieldValue(AssocFieldTxQueue, func(a *Assoc) string { return formatInt(a.TxQueue) })},
+	{"rx_queue", assocFieldValue(AssocFieldRxQueue, func(a *Assoc) string { return formatInt(a.RxQueue) })},
+	{"uid", assocFieldValue(AssocFieldUid, func(a *Assoc) string { return formatUint(a.Uid) })},
+	{"inode", assocFieldValue(AssocFieldInode, func(a *Assoc) string { return formatUint(a.Inode) })},
+	{"lport", assocFieldValue(AssocFieldLPort, func(a *Assoc) string { return formatInt(a.LPort) })},
+	{"rport", assocFieldValue(AssocFieldRPort, func(a *Assoc) string { return formatInt(a.RPort) })},
+	{"laddrs", func(a *Assoc) string { return strings.Join(a.LAddrs, " ") }},
+	{"raddrs", func(a *Assoc) string { return strings.Join(a.RAddrs, " ") }},
+	{"hbint", assocFieldValue(AssocFieldHbint, func(a *Assoc) string { return formatUint(a.Hbint) })},
+	{"ins", assocFieldValue(AssocFieldIns, func(a *Assoc) string { return formatInt(a.Ins) })},
+	{"outs", assocFieldValue(AssocFieldOuts, func(a *Assoc) string { return formatInt(a.Outs) })},
+	{"maxrt", assocFieldValue(AssocFieldMaxrt, func(a *Assoc) string { return formatInt(a.Maxrt) })},
+	{"t1x", assocFieldValue(AssocFieldT1x, func(a *Assoc) string { return formatInt(a.T1x) })},
+	{"t2x", assocFieldValue(AssocFieldT2x, func(a *Assoc) string { return formatInt(a.T2x) })},
+	{"rtxc", assocFieldValue(AssocFieldRtxc, func(a *Assoc) string { return formatInt(a.Rtxc) })},
+	{"wmema", assocFieldValue(AssocFieldWmema, func(a *Assoc) string { return formatInt(a.Wmema) })},
+	{"wmemq", assocFieldValue(AssocFieldWmemq, func(a *Assoc) string { return formatInt(a.Wmemq) })},
+	{"sndbuf", assocFieldValue(AssocFieldSndbuf, func(a *Assoc) string { return formatInt(a.Sndbuf) })},
+	{"rcvbuf", assocFieldValue(AssocFieldRcvbuf, func(a *Assoc) string { return formatInt(a.Rcvbuf) })},
+	{"primary_laddr", func(a *Assoc) string { return a.PrimaryLAddr }},
+	{"primary_raddr", func(a *Assoc) string { return a.PrimaryRAddr }},
+	{"owners", func(a *Assoc) string { return formatProcesses(a.Owners) }},
+	{"user_name", func(a *Assoc) string { return a.UserName }},
+	{"container_id", func(a *Assoc) string { return containerID(a.Container) }},
+	{"pod_uid", func(a *Assoc) string { return podUID(a.Container) }},
+}
+
+// assocFieldValue returns the value of the column, which is empty if the field is absent.
+func assocFieldValue(field AssocField, value func(a *Assoc) string) func(a *Assoc) string {
+	return func(a *Assoc) string {
+		if !a.Has(field) {
+			return ""
+		}
+		return value(a)
+	}
+}
+
+var epsCSVColumns = []csvColumn[*EPS]{
+	{"endpt", func(e *EPS) string { return formatUint(e.Endpt) }},
+	{"sock", func(e *EPS) string { return formatUint(e.Sock) }},
+	{"sty", func(e *EPS) string { return formatText(e.Sty) }},
+	{"sst", func(e *EPS) string { return formatText(e.Sst) }},
+	{"hbkt", func(e *EPS) string { return formatInt(e.Hbkt) }},
+	{"lport", func(e *EPS) string { return formatInt(e.LPort) }},
+	{"uid", func(e *EPS) string { return formatUint(e.Uid) }},
+	{"inode", func(e *EPS) string { return formatUint(e.Inode) }},
+	{"laddrs", func(e *EPS) string { return strings.Join(e.LAddrs, " ") }},
+	{"owners", func(e *EPS) string { return formatProcesses(e.Owners) }},
+	{"user_name", func(e *EPS) string { return e.UserName }},
+	{"container_id", func(e *EPS) string { return containerID(e.Container) }},
+	{"pod_uid", func(e *EPS) string { return podUID(e.Container) }},
+}
+
+var remaddrCSVColumns = []csvColumn[*Remaddr]{
+	{"addr", func(r *Remaddr) string { return r.Addr }},
+	{"assoc_id", func(r *Remaddr) string { return formatInt(r.AssocID) }},
+	{"hb_act", func(r *Remaddr) string { return formatInt(r.HbAct) }},
+	{"rto", func(r *Remaddr) string { return formatUint(r.RTO) }},
+	{"max_path_rtx", func(r *Remaddr) string { return formatInt(r.MaxPathRtx) }},
+	{"rem_addr_rtx", func(r *Remaddr) string { return formatInt(r.RemAddrRtx) }},
+	{"start", func(r *Remaddr) string { return formatInt(r.Start) }},
+	{"state", func(r *Remaddr) string { return formatText(r.State) }},
+}
+
+// pathRow is a row of the CSV encoding of the association views; an association joined with one of its paths.
+type pathRow struct {
+	assoc *Assoc
+	path  *Remaddr
+}
+
+// pathCSVColumns are the columns of the association followed by the columns of the path, which are prefixed with `path_`.
+// The path columns are empty if the association has no path.
+var pathCSVColumns = func() []csvColumn[*pathRow] {
+	columns := make([]csvColumn[*pathRow], 0, len(assocCSVColumns)+len(remaddrCSVColumns))
+	for _, column := range assocCSVColumns {
+		value := column.value
+		columns = append(columns, csvColumn[*pathRow]{column.name, func(r *pathRow) string { return value(r.assoc) }})
+	}
+	for _, column := range remaddrCSVColumns {
+		if column.name == "assoc_id" {
+			continue
+		}
+		value := column.value
+		columns = append(columns, csvColumn[*pathRow]{"path_" + column.name, func(r *pathRow) string {
+			if r.path == nil {
+				return ""
+			}
+			return value(r.path)
+		}})
+	}
+	return columns
+}()
+
+// EncodeAssocs writes the assocs to the writer in the encoding.
+func EncodeAssocs(w io.Writer, enc Encoding, assocs []*Assoc) error {
+	return encodeRecords(w, enc, assocs, func(cw *csv.Writer) error {
+		return writeCSV(cw, assocCSVColumns, assocs)
+	})
+}
+
+// EncodeEPS writes the EPS records to the writer in the encoding.
+func EncodeEPS(w io.Writer, enc Encoding, epses []*EPS) error {
+	return encodeRecords(w, enc, epses, func(cw *csv.Writer) error {
+		return writeCSV(cw, epsCSVColumns, epses)
+	})
+}
+
+// EncodeRemaddrs writes the remaddr records to the writer in the encoding.
+func EncodeRemaddrs(w io.Writer, enc Encoding, remaddrs []*Remaddr) error {
+	return encodeRecords(w, enc, remaddrs, func(cw *csv.Writer) error {
+		return writeCSV(cw, remaddrCSVColumns, remaddrs)
+	})
+}
+
+// EncodeAssociationViews writes the associations with their paths to the writer in the encoding.
+// The structured encodings nest the paths in each association. The CSV encoding writes a row per path instead;
+// see pathCSVColumns for the columns.
+func EncodeAssociationViews(w io.Writer, enc Encoding, views []*AssociationView) error {
+	return encodeRecords(w, enc, views, func(cw *csv.Writer) error {
+		rows := make([]*pathRow, 0, len(views))
+		for _, view := range views {
+			if len(view.Paths) <= 0 {
+				rows = append(rows, &pathRow{assoc: view.Assoc})
+				continue
+			}
+			for _, path := range view.Paths {
+				rows = append(rows, &pathRow{assoc: view.Assoc, path: path})
+			}
+		}
+		return writeCSV(cw, pathCSVColumns, rows)
+	})
+}
+
+func encodeRecords[T any](w io.Writer, enc Encoding, records []T, encodeCSV func(cw *csv.Writer) error) error {
+	if records == nil {
+		records = make([]T, 0) // not to encode as null
+	}
+
+	switch enc {
+	case EncodingJSON:
+		return json.NewEncoder(w).Encode(records)
+	case EncodingJSONLines:
+		encoder := json.NewEncoder(w)
+		for _, record := range records {
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	case EncodingCSV:
+		cw := csv.NewWriter(w)
+		if err := encodeCSV(cw); err != nil {
+			return err
+		}
+		cw.Flush()
+		return cw.Error()
+	case EncodingYAML:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(records); err != nil {
+			return err
+		}
+		return encoder.Close()
+	default:
+		return fmt.Errorf("%q: %w", enc, ErrUnknownEncoding)
+	}
+}
+
+func writeCSV[T any](cw *csv.Writer, columns []csvColumn[T], records []T) error {
+	row := make([]string, len(columns))
+	for i, column := range columns {
+		row[i] = column.name
+	}
+	if err := cw.Write(row); err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		for i, column := range columns {
+			row[i] = column.value(record)
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func containerID(c *Container) string {
+	if c == nil {
+		return ""
+	}
+	return c.ID
+}
+
+func podUID(c *Container) string {
+	if c == nil {
+		return ""
+	}
+	return c.PodUID
+}
+
+// formatText formats the value in the same way as the JSON and YAML encodings.
+func formatText(v encoding.TextMarshaler) string {
+	text, _ := v.MarshalText() // the enums of this package never fail
+	return string(text)
+}
+
+func formatInt(v int64) string {
+	return strconv.FormatInt(v, 10)
+}
+
+func formatUint(v uint64) string {
+	return strconv.FormatUint(v, 10)
+}
+
+// formatProcesses formats the processes as `<comm>/<pid>` separated by spaces.
+func formatProcesses(processes []*Process) string {
+	formatted := make([]string, len(processes))
+	for i, process := range processes {
+		formatted[i] = process.Comm + "/" + strconv.Itoa(process.PID)
+	}
+	return strings.Join(formatted, " ")
+}
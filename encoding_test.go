@@ -0,0 +1,173 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func newEncodingTestAssocs() []*Assoc {
+	return []*Assoc{
+		{
+			AssocId:      60,
+			Sty:          SocketTypeOneToOne,
+			Sst:          SocketStateEstablished,
+			St:           AssocStateEstablished,
+			LPort:        3868,
+			RPort:        54321,
+			LAddrs:       []string{"10.0.0.1"},
+			RAddrs:       []string{"10.0.0.2", "10.0.0.3"},
+			PrimaryRAddr: "10.0.0.2",
+			Rtxc:         4,
+			Owners:       []*Process{{PID: 123, Comm: "diameterd", Cmdline: []string{"diameterd", "-c", "/etc/diameterd.conf"}}},
+		},
+		{
+			AssocId: 61,
+			St:      AssocStateShutdownPending,
+			LPort:   2905,
+			RPort:   54322,
+			LAddrs:  []string{"10.0.0.1"},
+			RAddrs:  []string{"192.168.0.4"},
+			Absent:  AssocFieldWmema | AssocFieldWmemq | AssocFieldSndbuf | AssocFieldRcvbuf,
+		},
+	}
+}
+
+func TestParseEncoding(t *testing.T) {
+	for name, expected := range map[string]Encoding{
+		"json":   EncodingJSON,
+		"JSONL":  EncodingJSONLines,
+		"ndjson": EncodingJSONLines,
+		"csv":    EncodingCSV,
+		"yml":    EncodingYAML,
+	} {
+		enc, err := ParseEncoding(name)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, enc)
+	}
+
+	_, err := ParseEncoding("xml")
+	assert.ErrorIs(t, err, ErrUnknownEncoding)
+}
+
+func TestEncodeAssocs_JSON(t *testing.T) {
+	assocs := newEncodingTestAssocs()
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, EncodeAssocs(buf, EncodingJSON, assocs))
+	assert.Contains(t, buf.String(), `"assoc_id":60,`)
+	assert.Contains(t, buf.String(), `"st":"ESTABLISHED",`)
+	assert.Contains(t, buf.String(), `"owners":[{"pid":123,"comm":"diameterd","cmdline":["diameterd","-c","/etc/diameterd.conf"]}]`)
+
+	var decoded []*Assoc
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, assocs, decoded)
+}
+
+func TestEncodeAssocs_JSONLines(t *testing.T) {
+	buf := &bytes.Buffer{}
+	assert.NoError(t, EncodeAssocs(buf, EncodingJSONLines, newEncodingTestAssocs()))
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	assert.Len(t, lines, 2)
+
+	var decoded Assoc
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &decoded))
+	assert.EqualValues(t, 61, decoded.AssocId)
+	assert.Equal(t, AssocStateShutdownPending, decoded.St)
+	assert.Contains(t, lines[1], `"absent":"wmema wmemq sndbuf rcvbuf"`)
+	assert.NotContains(t, lines[0], `"absent"`)
+	assert.False(t, decoded.Has(AssocFieldRcvbuf))
+}
+
+func TestEncodeAssocs_CSV(t *testing.T) {
+	buf := &bytes.Buffer{}
+	assert.NoError(t, EncodeAssocs(buf, EncodingCSV, newEncodingTestAssocs()))
+	assert.Equal(t, `assoc,sock,sty,sst,st,hbkt,assoc_id,tx_queue,rx_queue,uid,inode,lport,rport,laddrs,raddrs,hbint,ins,outs,maxrt,t1x,t2x,rtxc,wmema,wmemq,sndbuf,rcvbuf,primary_laddr,primary_raddr,owners,user_name,container_id,pod_uid
+0,0,ONE_TO_ONE,ESTABLISHED,ESTABLISHED,0,60,0,0,0,0,3868,54321,10.0.0.1,10.0.0.2 10.0.0.3,0,0,0,0,0,0,4,0,0,0,0,,10.0.0.2,diameterd/123,,,
+0,0,ONE_TO_MANY,0,SHUTDOWN_PENDING,0,61,0,0,0,0,2905,54322,10.0.0.1,192.168.0.4,0,0,0,0,0,0,0,,,,,,,,,,
+`, buf.String())
+}
+
+func TestEncodeAssocs_YAML(t *testing.T) {
+	assocs := newEncodingTestAssocs()
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, EncodeAssocs(buf, EncodingYAML, assocs))
+	assert.Contains(t, buf.String(), "- assoc: 0\n  sock: 0\n  sty: ONE_TO_ONE\n")
+	assert.Contains(t, buf.String(), "  absent: wmema wmemq sndbuf rcvbuf\n")
+
+	var decoded []*Assoc
+	assert.NoError(t, yaml.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, assocs, decoded)
+}
+
+func TestAssocField_Text(t *testing.T) {
+	text, err := (AssocFieldAssocId | AssocFieldRcvbuf).MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, "assoc_id rcvbuf", string(text))
+
+	var fields AssocField
+	assert.NoError(t, fields.UnmarshalText(text))
+	assert.Equal(t, AssocFieldAssocId|AssocFieldRcvbuf, fields)
+	assert.ErrorIs(t, fields.UnmarshalText([]byte("assoc_id unknown")), ErrUnknownAssocField)
+}
+
+func TestEncodeEPS(t *testing.T) {
+	epses := []*EPS{
+		{Sty: SocketTypeOneToOne, Sst: SocketStateListen, LPort: 3868, Inode: 227065, LAddrs: []string{"10.0.0.1", "10.0.0.5"}, UserName: "diameter"},
+	}
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, EncodeEPS(buf, EncodingCSV, epses))
+	assert.Equal(t, `endpt,sock,sty,sst,hbkt,lport,uid,inode,laddrs,owners,user_name,container_id,pod_uid
+0,0,ONE_TO_ONE,LISTEN,0,3868,0,227065,10.0.0.1 10.0.0.5,,diameter,,
+`, buf.String())
+
+	buf.Reset()
+	assert.NoError(t, EncodeEPS(buf, EncodingJSON, nil))
+	assert.Equal(t, "[]\n", buf.String())
+}
+
+func TestEncodeRemaddrs(t *testing.T) {
+	remaddrs := []*Remaddr{
+		{Addr: "10.0.0.2", AssocID: 60, HbAct: 1, RTO: 3000, MaxPathRtx: 5, State: TransportStateActive},
+	}
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, EncodeRemaddrs(buf, EncodingJSONLines, remaddrs))
+	assert.Equal(t, `{"addr":"10.0.0.2","assoc_id":60,"hb_act":1,"rto":3000,"max_path_rtx":5,"rem_addr_rtx":0,"start":0,"state":"ACTIVE"}
+`, buf.String())
+}
+
+func TestEncodeAssociationViews(t *testing.T) {
+	assocs := newEncodingTestAssocs()
+	views := NewSnapshot(assocs, []*Remaddr{
+		{Addr: "10.0.0.2", AssocID: 60, RTO: 3000, State: TransportStateActive},
+		{Addr: "10.0.0.3", AssocID: 60, RTO: 1000, State: TransportStateInactive},
+	}).Associations
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, EncodeAssociationViews(buf, EncodingCSV, views))
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	assert.Len(t, lines, 4)
+	assert.True(t, strings.HasSuffix(lines[0], ",pod_uid,path_addr,path_hb_act,path_rto,path_max_path_rtx,path_rem_addr_rtx,path_start,path_state"))
+	assert.True(t, strings.HasSuffix(lines[1], ",10.0.0.2,0,3000,0,0,0,ACTIVE"))
+	assert.True(t, strings.HasSuffix(lines[2], ",10.0.0.3,0,1000,0,0,0,INACTIVE"))
+	assert.True(t, strings.HasSuffix(lines[3], ",,,,,,,,,,,,,"))
+
+	buf.Reset()
+	assert.NoError(t, EncodeAssociationViews(buf, EncodingJSON, views))
+	var decoded []*AssociationView
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, views, decoded)
+}
+
+func TestEncodeAssocs_WithUnknownEncoding(t *testing.T) {
+	err := EncodeAssocs(&bytes.Buffer{}, Encoding("xml"), newEncodingTestAssocs())
+	assert.ErrorIs(t, err, ErrUnknownEncoding)
+}
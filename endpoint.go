@@ -0,0 +1,143 @@
+package parser
+
+import (
+	"net/netip"
+)
+
+// Endpoint is an SCTP endpoint with the associations that belong to it.
+type Endpoint struct {
+	EPS *EPS
+	// Assocs are the associations that have been established on the endpoint, in the order of the assocs contents.
+	Assocs []*Assoc
+}
+
+// AssocCount returns the number of the associations that belong to the endpoint.
+func (e *Endpoint) AssocCount() int {
+	return len(e.Assocs)
+}
+
+// LinkEndpoints attaches each assoc to the endpoint that it belongs to.
+// It returns the endpoints in the order of eps, and the assocs that don't belong to any endpoint.
+//
+// An assoc belongs to:
+//
+// 1. the listening endpoint that has the same local port and the local addresses covering the ones of the assoc.
+// If there are multiple such endpoints, the one of the same socket (i.e. the same inode) is preferred;
+// a one-to-many style socket has its associations on the listening endpoint itself,
+// whereas a one-to-one style socket has each accepted association on a new socket.
+// 2. otherwise, the endpoint of the same socket; e.g. the association has been initiated by the local side.
+func LinkEndpoints(eps []*EPS, assocs []*Assoc) ([]*Endpoint, []*Assoc) {
+	endpoints := make([]*Endpoint, len(eps))
+	byInode := make(map[uint64]*Endpoint, len(eps))
+	listeningByPort := make(map[int64][]*listeningEndpoint)
+	for i, e := range eps {
+		endpoint := &Endpoint{
+			EPS:    e,
+			Assocs: make([]*Assoc, 0),
+		}
+		endpoints[i] = endpoint
+
+		if _, exists := byInode[e.Inode]; !exists {
+			byInode[e.Inode] = endpoint
+		}
+		if e.Sst.IsListening() {
+			laddrIPs, ok := addrIPs(e.LAddrs, e.LAddrIPs)
+			if !ok {
+				continue // the addresses can't be told to cover any
+			}
+			listeningByPort[e.LPort] = append(listeningByPort[e.LPort], &listeningEndpoint{
+				endpoint: endpoint,
+				laddrIPs: laddrIPs,
+			})
+		}
+	}
+
+	unlinked := make([]*Assoc, 0)
+	for _, assoc := range assocs {
+		endpoint := findListeningEndpoint(listeningByPort[assoc.LPort], assoc)
+		if endpoint == nil {
+			endpoint = byInode[assoc.Inode]
+		}
+		if endpoint == nil {
+			unlinked = append(unlinked, assoc)
+			continue
+		}
+		endpoint.Assocs = append(endpoint.Assocs, assoc)
+	}
+
+	return endpoints, unlinked
+}
+
+// listeningEndpoint is a listening endpoint with its parsed local addresses.
+type listeningEndpoint struct {
+	endpoint *Endpoint
+	laddrIPs []netip.Addr
+}
+
+func findListeningEndpoint(candidates []*listeningEndpoint, assoc *Assoc) *Endpoint {
+	if len(candidates) <= 0 {
+		return nil
+	}
+
+	laddrIPs, ok := addrIPs(assoc.LAddrs, assoc.LAddrIPs)
+	if !ok {
+		return nil // the addresses can't be told to be covered
+	}
+	var found *Endpoint
+	for _, candidate := range candidates {
+		if !coversAddrs(candidate.laddrIPs, laddrIPs) {
+			continue
+		}
+		if candidate.endpoint.EPS.Inode == assoc.Inode {
+			return candidate.endpoint
+		}
+		if found == nil {
+			found = candidate.endpoint
+		}
+	}
+	return found
+}
+
+// addrIPs returns the parsed forms of the addresses; i.e. ips as it is if the record has been parsed,
+// otherwise the addresses are parsed here (e.g. the record has been built by hand).
+// It returns false if any of the addresses is invalid, so that the addresses are not compared partially.
+func addrIPs(addrs []string, ips []netip.Addr) ([]netip.Addr, bool) {
+	if len(ips) == len(addrs) {
+		return ips, true
+	}
+	ips = make([]netip.Addr, len(addrs))
+	for i, addr := range addrs {
+		ip, err := netip.ParseAddr(addr)
+		if err != nil {
+			return nil, false
+		}
+		ips[i] = ip
+	}
+	return ips, true
+}
+
+// coversAddrs returns whether all the addrs are in the boundAddrs.
+// A wildcard address (e.g. `0.0.0.0` or `::`) in the boundAddrs covers any address of the family;
+// the IPv6 wildcard address covers IPv4 addresses too.
+func coversAddrs(boundAddrs []netip.Addr, addrs []netip.Addr) bool {
+	for _, addr := range addrs {
+		covered := false
+		for _, boundAddr := range boundAddrs {
+			if coversAddr(boundAddr, addr) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return false
+		}
+	}
+	return true
+}
+
+func coversAddr(boundAddr netip.Addr, addr netip.Addr) bool {
+	if boundAddr.IsUnspecified() {
+		return boundAddr.Is6() || addr.Unmap().Is4()
+	}
+	return boundAddr.Unmap() == addr.Unmap()
+}
@@ -0,0 +1,114 @@
+package parser
+
+import (
+	"bufio"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinkEndpoints(t *testing.T) {
+	eps, err := ParseEPS(bufio.NewScanner(strings.NewReader(`ENDPT     SOCK   STY SST HBKT LPORT   UID INODE LADDRS
+0        0 2   10  24   3868     0 1000 10.0.0.1 10.0.0.2
+0        0 0   10  16   2905     0 2000 0.0.0.0
+0        0 2   1  24   3868     0 1001 10.0.0.1 10.0.0.2
+0        0 2   1  24   3868     0 1002 10.0.0.1 10.0.0.2
+0        0 2   1  11   40000     0 3000 10.0.0.1
+`)))
+	assert.NoError(t, err)
+	assocs, err := ParseAssocs(bufio.NewScanner(strings.NewReader(`ASSOC     SOCK   STY SST ST HBKT ASSOC-ID TX_QUEUE RX_QUEUE UID INODE LPORT RPORT LADDRS <-> RADDRS HBINT INS OUTS MAXRT T1X T2X RTXC wmema wmemq sndbuf rcvbuf
+0 0 2 1 3 0 1 0 0 0 1001 3868 50000 10.0.0.1 10.0.0.2 <-> *192.168.0.1 30000 10 10 10 0 0 0 1 0 212992 212992
+0 0 2 1 3 0 2 0 0 0 1002 3868 50001 10.0.0.1 10.0.0.2 <-> *192.168.0.2 30000 10 10 10 0 0 0 1 0 212992 212992
+0 0 0 10 3 0 3 0 0 0 2000 2905 2905 10.0.0.1 <-> *192.168.0.3 30000 10 10 10 0 0 0 1 0 212992 212992
+0 0 2 1 3 0 4 0 0 0 3000 40000 3868 10.0.0.1 <-> *192.168.0.4 30000 10 10 10 0 0 0 1 0 212992 212992
+0 0 2 1 3 0 5 0 0 0 4000 50000 3868 10.0.0.1 <-> *192.168.0.5 30000 10 10 10 0 0 0 1 0 212992 212992
+`)))
+	assert.NoError(t, err)
+
+	endpoints, unlinked := LinkEndpoints(eps, assocs)
+
+	assert.Len(t, endpoints, 5)
+	// one-to-one style listener on 3868: the accepted associations have their own sockets
+	assert.Equal(t, eps[0], endpoints[0].EPS)
+	assert.Equal(t, []*Assoc{assocs[0], assocs[1]}, endpoints[0].Assocs)
+	assert.Equal(t, 2, endpoints[0].AssocCount())
+	// one-to-many style listener on the wildcard address
+	assert.Equal(t, []*Assoc{assocs[2]}, endpoints[1].Assocs)
+	// the sockets of the accepted associations
+	assert.Equal(t, 0, endpoints[2].AssocCount())
+	assert.Equal(t, 0, endpoints[3].AssocCount())
+	// the client socket
+	assert.Equal(t, []*Assoc{assocs[3]}, endpoints[4].Assocs)
+
+	assert.Equal(t, []*Assoc{assocs[4]}, unlinked)
+}
+
+func TestLinkEndpoints_PreferSameSocket(t *testing.T) {
+	eps := []*EPS{
+		{LPort: 2905, Sst: SocketStateListen, Inode: 1, LAddrs: []string{"::"}},
+		{LPort: 2905, Sst: SocketStateListen, Inode: 2, LAddrs: []string{"10.0.0.1"}},
+	}
+	assocs := []*Assoc{
+		{LPort: 2905, Inode: 2, LAddrs: []string{"10.0.0.1"}},
+		{LPort: 2905, Inode: 3, LAddrs: []string{"10.0.0.1"}},
+		{LPort: 2905, Inode: 3, LAddrs: []string{"10.0.0.2"}},
+	}
+
+	endpoints, unlinked := LinkEndpoints(eps, assocs)
+
+	assert.Equal(t, []*Assoc{assocs[1], assocs[2]}, endpoints[0].Assocs)
+	assert.Equal(t, []*Assoc{assocs[0]}, endpoints[1].Assocs)
+	assert.Empty(t, unlinked)
+}
+
+func TestCoversAddrs(t *testing.T) {
+	ips := func(addrs ...string) []netip.Addr {
+		parsed, ok := addrIPs(addrs, nil)
+		assert.True(t, ok)
+		return parsed
+	}
+
+	assert.True(t, coversAddrs(ips("10.0.0.1", "10.0.0.2"), ips("10.0.0.2")))
+	assert.False(t, coversAddrs(ips("10.0.0.1"), ips("10.0.0.1", "10.0.0.2")))
+	assert.True(t, coversAddrs(ips("0.0.0.0"), ips("10.0.0.1")))
+	assert.False(t, coversAddrs(ips("0.0.0.0"), ips("2001:db8::1")))
+	assert.True(t, coversAddrs(ips("0000:0000:0000:0000:0000:0000:0000:0000"), ips("2001:db8::1", "10.0.0.1")))
+	assert.True(t, coversAddrs(ips("2001:0db8:0000:0000:0000:0000:0000:0001"), ips("2001:db8::1")))
+}
+
+func TestAddrIPs(t *testing.T) {
+	parsed := []netip.Addr{netip.MustParseAddr("10.0.0.1")}
+	ips, ok := addrIPs([]string{"10.0.0.1"}, parsed)
+	assert.True(t, ok)
+	assert.Equal(t, parsed, ips)
+
+	ips, ok = addrIPs([]string{"10.0.0.2", "10.0.0.3"}, nil)
+	assert.True(t, ok)
+	assert.Equal(t, []netip.Addr{netip.MustParseAddr("10.0.0.2"), netip.MustParseAddr("10.0.0.3")}, ips)
+
+	_, ok = addrIPs([]string{"10.0.0.2", "invalid"}, nil)
+	assert.False(t, ok)
+}
+
+func TestLinkEndpoints_InvalidAddrs(t *testing.T) {
+	eps := []*EPS{
+		{LPort: 2905, Sst: SocketStateListen, Inode: 1, LAddrs: []string{"10.0.0.1"}},
+		{LPort: 2905, Sst: SocketStateListen, Inode: 2, LAddrs: []string{"invalid"}},
+	}
+	assocs := []*Assoc{
+		// an invalid address must not be skipped to be covered by the listener of the other address
+		{LPort: 2905, Inode: 3, LAddrs: []string{"10.0.0.1", "invalid"}},
+		// the listener of an invalid address covers nothing
+		{LPort: 2905, Inode: 4, LAddrs: []string{"10.0.0.2"}},
+		// the endpoint of the same socket is still linked
+		{LPort: 2905, Inode: 2, LAddrs: []string{"10.0.0.3"}},
+	}
+
+	endpoints, unlinked := LinkEndpoints(eps, assocs)
+
+	assert.Empty(t, endpoints[0].Assocs)
+	assert.Equal(t, []*Assoc{assocs[2]}, endpoints[1].Assocs)
+	assert.Equal(t, []*Assoc{assocs[0], assocs[1]}, unlinked)
+}
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"errors"
 	"fmt"
+	"net/netip"
 	"strconv"
 	"strings"
 )
@@ -13,23 +14,55 @@ var (
 	ErrInvalidEPSFormat             = errors.New("invalid EPS format")
 )
 
+const epsHeader = "ENDPT     SOCK   STY SST HBKT LPORT   UID INODE LADDRS"
+
+var (
+	epsAddrColumns   = []string{"LADDRS"}
+	defaultEPSLayout = mustParseColumnLayout(epsHeader, epsAddrColumns)
+)
+
+// epsColumns maps the header names of EPS to the functions that set the column value to an EPS.
+// Columns that are not in this map are ignored.
+var epsColumns = map[string]func(e *EPS, v string) error{
+	"ENDPT": func(e *EPS, v string) (err error) { e.Endpt, err = strconv.ParseUint(v, 16, 64); return },
+	"SOCK":  func(e *EPS, v string) (err error) { e.Sock, err = strconv.ParseUint(v, 16, 64); return },
+	"STY":   func(e *EPS, v string) (err error) { e.Sty, err = parseIntColumn[SocketType](v); return },
+	"SST":   func(e *EPS, v string) (err error) { e.Sst, err = parseIntColumn[SocketState](v); return },
+	"HBKT":  func(e *EPS, v string) (err error) { e.Hbkt, err = strconv.ParseInt(v, 10, 64); return },
+	"LPORT": func(e *EPS, v string) (err error) { e.LPort, err = strconv.ParseInt(v, 10, 64); return },
+	"UID":   func(e *EPS, v string) (err error) { e.Uid, err = strconv.ParseUint(v, 10, 64); return },
+	"INODE": func(e *EPS, v string) (err error) { e.Inode, err = strconv.ParseUint(v, 10, 64); return },
+}
+
 // EPS represents the structure of SCTP EPS.
 type EPS struct {
-	Endpt  uint64
-	Sock   uint64
-	Sty    int64
-	Sst    int64
-	Hbkt   int64
-	LPort  int64
-	Uid    uint64
-	Inode  uint64
-	LAddrs []string
+	Endpt  uint64      `json:"endpt" yaml:"endpt"`
+	Sock   uint64      `json:"sock" yaml:"sock"`
+	Sty    SocketType  `json:"sty" yaml:"sty"`
+	Sst    SocketState `json:"sst" yaml:"sst"`
+	Hbkt   int64       `json:"hbkt" yaml:"hbkt"`
+	LPort  int64       `json:"lport" yaml:"lport"`
+	Uid    uint64      `json:"uid" yaml:"uid"`
+	Inode  uint64      `json:"inode" yaml:"inode"`
+	LAddrs []string    `json:"laddrs" yaml:"laddrs"`
+
+	// LAddrIPs is the parsed form of LAddrs. It is in the same order as LAddrs.
+	LAddrIPs []netip.Addr `json:"-" yaml:"-"`
+
+	// Owners are the processes that have the socket open. This is set by SocketOwners.AnnotateEPS.
+	Owners []*Process `json:"owners,omitempty" yaml:"owners,omitempty"`
+	// UserName is the name of the user of Uid. This is set by UserResolver.AnnotateEPS.
+	UserName string `json:"user_name,omitempty" yaml:"user_name,omitempty"`
+	// Container is the container whose network namespace the endpoint is in. This is set by Container.AnnotateEPS.
+	Container *Container `json:"container,omitempty" yaml:"container,omitempty"`
 }
 
 // ParseEPS parses SCTP EPS contents; for example the contents of `/proc/net/sctp/eps` file.
 //
+// The columns are located according to the header line, so the order of the columns may differ from the example below.
+//
 // - input: the contents of SCTP EPS
-// - noHeader: this has to be true if the input doesn't have a header line (default: `false`)
+// - noHeader: this has to be true if the input doesn't have a header line (default: `false`). In that case, the columns are expected to be in the order of the example below.
 //
 // example input:
 // ```
@@ -38,75 +71,120 @@ type EPS struct {
 // 0        0 2   10  16   54321     0 232851 127.0.0.3
 // ```
 func ParseEPS(input *bufio.Scanner, noHeader ...bool) ([]*EPS, error) {
-	// implementation memo:
-	// https://github.com/torvalds/linux/blob/dcc0b49040c70ad827a7f3d58a21b01fdb14e749/net/sctp/proc.c#L179
+	scanner := NewEPSScanner(input, noHeader...)
 
-	lineNum := 1
-	if len(noHeader) <= 0 || !noHeader[0] {
-		input.Scan() // skip a header line
-		lineNum++
+	epses := make([]*EPS, 0)
+	for scanner.Next() {
+		epses = append(epses, scanner.Record())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
 	}
 
-	epses := make([]*EPS, 0)
+	return epses, nil
+}
 
-	for input.Scan() {
-		if input.Text() == "" {
-			lineNum++
-			continue
-		}
+// EPSScanner reads SCTP EPS contents one record at a time.
+// This is useful to process a large number of records without holding all of them in memory.
+type EPSScanner struct {
+	lineScanner
+	layout *columnLayout
+	record *EPS
+}
 
-		leaves := spacesRe.Split(strings.TrimSpace(input.Text()), -1)
-		if len(leaves) < 9 {
-			return nil, fmt.Errorf("at line #%d: %w", lineNum, ErrInsufficientNumberOfEPSItems)
-		}
+// NewEPSScanner returns a new EPSScanner that reads SCTP EPS contents from the input.
+//
+// - input: the contents of SCTP EPS
+// - noHeader: this has to be true if the input doesn't have a header line (default: `false`)
+func NewEPSScanner(input *bufio.Scanner, noHeader ...bool) *EPSScanner {
+	return &EPSScanner{
+		lineScanner: newLineScanner(input, noHeader),
+	}
+}
 
-		endpt, err := strconv.ParseUint(leaves[0], 16, 64)
-		if err != nil {
-			return nil, fmt.Errorf("ENDPT at line #%d: %w", lineNum, ErrInvalidEPSFormat)
-		}
-		sock, err := strconv.ParseUint(leaves[1], 16, 64)
-		if err != nil {
-			return nil, fmt.Errorf("SOCK at line #%d: %w", lineNum, ErrInvalidEPSFormat)
-		}
-		sty, err := strconv.ParseInt(leaves[2], 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("STY at line #%d: %w", lineNum, ErrInvalidEPSFormat)
-		}
-		sst, err := strconv.ParseInt(leaves[3], 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("SST at line #%d: %w", lineNum, ErrInvalidEPSFormat)
-		}
-		hbkt, err := strconv.ParseInt(leaves[4], 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("HBKT at line #%d: %w", lineNum, ErrInvalidEPSFormat)
-		}
-		lport, err := strconv.ParseInt(leaves[5], 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("LPORT at line #%d: %w", lineNum, ErrInvalidEPSFormat)
+// Next advances the scanner to the next EPS record, which will then be available through the Record method.
+// It returns false when the scan stops, either by reaching the end of the input or an error.
+func (s *EPSScanner) Next() bool {
+	s.record = nil
+
+	line, lineNum, ok := s.nextLine()
+	if !ok {
+		return false
+	}
+
+	if s.layout == nil {
+		if s.noHeader {
+			s.layout = defaultEPSLayout
+		} else {
+			layout, ok := parseColumnLayout(s.header, epsAddrColumns)
+			if !ok {
+				s.err = fmt.Errorf("header at line #1: %w", ErrInvalidEPSFormat)
+				return false
+			}
+			s.layout = layout
 		}
-		uid, err := strconv.ParseUint(leaves[6], 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("UID at line #%d: %w", lineNum, ErrInvalidEPSFormat)
+	}
+
+	eps, err := parseEPSLine(line, lineNum, s.layout)
+	if err != nil {
+		s.err = err
+		return false
+	}
+	s.record = eps
+
+	return true
+}
+
+// Record returns the most recent EPS record generated by a call to Next.
+func (s *EPSScanner) Record() *EPS {
+	return s.record
+}
+
+func parseEPSLine(line string, lineNum int, layout *columnLayout) (*EPS, error) {
+	// implementation memo:
+	// https://github.com/torvalds/linux/blob/dcc0b49040c70ad827a7f3d58a21b01fdb14e749/net/sctp/proc.c#L179
+
+	leaves := spacesRe.Split(strings.TrimSpace(line), -1)
+	leavesLen := len(leaves)
+	leadingLen := len(layout.leading)
+	trailingLen := len(layout.trailing)
+	if leavesLen < leadingLen+trailingLen+1 { // +1: at least one laddr
+		return nil, fmt.Errorf("at line #%d: %w", lineNum, ErrInsufficientNumberOfEPSItems)
+	}
+
+	eps := &EPS{}
+	for i, name := range layout.leading {
+		if err := setEPSColumn(eps, name, leaves[i], lineNum); err != nil {
+			return nil, err
 		}
-		inode, err := strconv.ParseUint(leaves[7], 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("INODE at line #%d: %w", lineNum, ErrInvalidEPSFormat)
+	}
+
+	endCursorForLaddrs := leavesLen - trailingLen
+	for i, name := range layout.trailing {
+		if err := setEPSColumn(eps, name, leaves[endCursorForLaddrs+i], lineNum); err != nil {
+			return nil, err
 		}
-		laddrs := leaves[8:]
-
-		lineNum++
-		epses = append(epses, &EPS{
-			Endpt:  endpt,
-			Sock:   sock,
-			Sty:    sty,
-			Sst:    sst,
-			Hbkt:   hbkt,
-			LPort:  lport,
-			Uid:    uid,
-			Inode:  inode,
-			LAddrs: laddrs,
-		})
 	}
 
-	return epses, nil
+	laddrs := leaves[leadingLen:endCursorForLaddrs]
+	laddrIPs, err := parseAddrs(laddrs)
+	if err != nil {
+		return nil, fmt.Errorf("LADDRS at line #%d: %s: %w", lineNum, err, ErrInvalidAddress)
+	}
+
+	eps.LAddrs = laddrs
+	eps.LAddrIPs = laddrIPs
+
+	return eps, nil
+}
+
+func setEPSColumn(eps *EPS, name string, value string, lineNum int) error {
+	set, ok := epsColumns[name]
+	if !ok {
+		return nil // unknown column
+	}
+	if err := set(eps, value); err != nil {
+		return fmt.Errorf("%s at line #%d: %w", name, lineNum, ErrInvalidEPSFormat)
+	}
+	return nil
 }
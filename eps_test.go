@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"fmt"
 	"log"
+	"net/netip"
 	"strings"
 	"testing"
 
@@ -18,26 +19,28 @@ func TestParseEPS(t *testing.T) {
 	eps, err := ParseEPS(bufio.NewScanner(strings.NewReader(input)))
 	assert.NoError(t, err)
 	assert.EqualValues(t, &EPS{
-		Endpt:  0,
-		Sock:   0,
-		Sty:    2,
-		Sst:    10,
-		Hbkt:   24,
-		LPort:  12345,
-		Uid:    0,
-		Inode:  227065,
-		LAddrs: []string{"127.0.0.1"},
+		Endpt:    0,
+		Sock:     0,
+		Sty:      2,
+		Sst:      10,
+		Hbkt:     24,
+		LPort:    12345,
+		Uid:      0,
+		Inode:    227065,
+		LAddrs:   []string{"127.0.0.1"},
+		LAddrIPs: []netip.Addr{netip.MustParseAddr("127.0.0.1")},
 	}, eps[0])
 	assert.EqualValues(t, &EPS{
-		Endpt:  0,
-		Sock:   0,
-		Sty:    2,
-		Sst:    10,
-		Hbkt:   16,
-		LPort:  54321,
-		Uid:    0,
-		Inode:  232851,
-		LAddrs: []string{"127.0.0.3"},
+		Endpt:    0,
+		Sock:     0,
+		Sty:      2,
+		Sst:      10,
+		Hbkt:     16,
+		LPort:    54321,
+		Uid:      0,
+		Inode:    232851,
+		LAddrs:   []string{"127.0.0.3"},
+		LAddrIPs: []netip.Addr{netip.MustParseAddr("127.0.0.3")},
 	}, eps[1])
 }
 
@@ -49,26 +52,28 @@ func TestParseEPS_MultipleLAddress(t *testing.T) {
 	eps, err := ParseEPS(bufio.NewScanner(strings.NewReader(input)))
 	assert.NoError(t, err)
 	assert.EqualValues(t, &EPS{
-		Endpt:  0,
-		Sock:   0,
-		Sty:    2,
-		Sst:    10,
-		Hbkt:   24,
-		LPort:  12345,
-		Uid:    0,
-		Inode:  227065,
-		LAddrs: []string{"127.0.0.1", "127.0.0.2"},
+		Endpt:    0,
+		Sock:     0,
+		Sty:      2,
+		Sst:      10,
+		Hbkt:     24,
+		LPort:    12345,
+		Uid:      0,
+		Inode:    227065,
+		LAddrs:   []string{"127.0.0.1", "127.0.0.2"},
+		LAddrIPs: []netip.Addr{netip.MustParseAddr("127.0.0.1"), netip.MustParseAddr("127.0.0.2")},
 	}, eps[0])
 	assert.EqualValues(t, &EPS{
-		Endpt:  0,
-		Sock:   0,
-		Sty:    2,
-		Sst:    10,
-		Hbkt:   16,
-		LPort:  54321,
-		Uid:    0,
-		Inode:  232851,
-		LAddrs: []string{"127.0.0.3", "127.0.0.4"},
+		Endpt:    0,
+		Sock:     0,
+		Sty:      2,
+		Sst:      10,
+		Hbkt:     16,
+		LPort:    54321,
+		Uid:      0,
+		Inode:    232851,
+		LAddrs:   []string{"127.0.0.3", "127.0.0.4"},
+		LAddrIPs: []netip.Addr{netip.MustParseAddr("127.0.0.3"), netip.MustParseAddr("127.0.0.4")},
 	}, eps[1])
 }
 
@@ -91,6 +96,51 @@ func TestParseEPS_WithInsufficientItems(t *testing.T) {
 	assert.Contains(t, err.Error(), "at line #2")
 }
 
+func TestParseEPS_WithReorderedColumns(t *testing.T) {
+	input := `SOCK ENDPT STY SST HBKT LPORT INODE UID LADDRS NEWCOL
+1 2 2 10 24 12345 227065 1000 127.0.0.1 127.0.0.2 999
+`
+	eps, err := ParseEPS(bufio.NewScanner(strings.NewReader(input)))
+	assert.NoError(t, err)
+	assert.EqualValues(t, &EPS{
+		Endpt:    2,
+		Sock:     1,
+		Sty:      2,
+		Sst:      10,
+		Hbkt:     24,
+		LPort:    12345,
+		Uid:      1000,
+		Inode:    227065,
+		LAddrs:   []string{"127.0.0.1", "127.0.0.2"},
+		LAddrIPs: []netip.Addr{netip.MustParseAddr("127.0.0.1"), netip.MustParseAddr("127.0.0.2")},
+	}, eps[0])
+}
+
+func TestParseEPS_WithInvalidAddress(t *testing.T) {
+	input := `ENDPT     SOCK   STY SST HBKT LPORT   UID INODE LADDRS
+0        0 2   10  24   12345     0 227065 127.0.0.1 localhost
+`
+	_, err := ParseEPS(bufio.NewScanner(strings.NewReader(input)))
+	assert.ErrorIs(t, err, ErrInvalidAddress)
+	assert.Contains(t, err.Error(), "LADDRS at line #2")
+}
+
+func TestEPSScanner(t *testing.T) {
+	input := `ENDPT     SOCK   STY SST HBKT LPORT   UID INODE LADDRS
+0        0 2   10  24   12345     0 227065 127.0.0.1
+0        0 2   10  16   54321     0 232851 127.0.0.3
+`
+	scanner := NewEPSScanner(bufio.NewScanner(strings.NewReader(input)))
+
+	assert.True(t, scanner.Next())
+	assert.EqualValues(t, 12345, scanner.Record().LPort)
+	assert.True(t, scanner.Next())
+	assert.EqualValues(t, 54321, scanner.Record().LPort)
+	assert.False(t, scanner.Next())
+	assert.Nil(t, scanner.Record())
+	assert.NoError(t, scanner.Err())
+}
+
 func ExampleParseEPS() {
 	input := `ENDPT     SOCK   STY SST HBKT LPORT   UID INODE LADDRS
 0        0 2   10  24   12345     0 227065 127.0.0.1
@@ -0,0 +1,137 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// assocTrailingFormats are the formats of the columns after the address lists, as the kernel prints them.
+var assocTrailingFormats = map[string]func(a *Assoc) string{
+	"HBINT":  func(a *Assoc) string { return fmt.Sprintf("%8d", a.Hbint) },
+	"INS":    func(a *Assoc) string { return fmt.Sprintf("%5d", a.Ins) },
+	"OUTS":   func(a *Assoc) string { return fmt.Sprintf("%5d", a.Outs) },
+	"MAXRT":  func(a *Assoc) string { return fmt.Sprintf("%4d", a.Maxrt) },
+	"T1X":    func(a *Assoc) string { return fmt.Sprintf("%4d", a.T1x) },
+	"T2X":    func(a *Assoc) string { return fmt.Sprintf("%4d", a.T2x) },
+	"RTXC":   func(a *Assoc) string { return fmt.Sprintf("%8d", a.Rtxc) },
+	"wmema":  func(a *Assoc) string { return fmt.Sprintf("%8d", a.Wmema) },
+	"wmemq":  func(a *Assoc) string { return fmt.Sprintf("%8d", a.Wmemq) },
+	"sndbuf": func(a *Assoc) string { return fmt.Sprintf("%8d", a.Sndbuf) },
+	"rcvbuf": func(a *Assoc) string { return fmt.Sprintf("%8d", a.Rcvbuf) },
+}
+
+// WriteAssocs writes the assocs in the format of `/proc/net/sctp/assocs`, including the header line.
+// ParseAssocs parses the output back into the same records.
+//
+// The output is byte-compatible with the kernel's one, except that the ASSOC and SOCK columns
+// are printed without the leading zeros of the kernel's hashed pointers.
+// The layout is chosen by Assoc.Absent of the first record; e.g. the records that have been parsed from
+// an older kernel's contents are written in that older layout.
+func WriteAssocs(w io.Writer, assocs []*Assoc) error {
+	// implementation memo:
+	// https://github.com/torvalds/linux/blob/dcc0b49040c70ad827a7f3d58a21b01fdb14e749/net/sctp/proc.c#L243
+
+	index := 0
+	if len(assocs) > 0 {
+		for i, layout := range assocsLayouts {
+			if absentAssocFields(layout) == assocs[0].Absent {
+				index = i
+				break
+			}
+		}
+	}
+	layout := assocsLayouts[index]
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, " %s\n", assocsHeaders[index])
+	for _, a := range assocs {
+		fmt.Fprintf(bw, "%8x %8x %-3d %-3d %-2d %-4d %4d %8d %8d %7d %5d %-5d %5d  ",
+			a.Assoc, a.Sock, a.Sty, a.Sst, a.St, a.Hbkt,
+			a.AssocId, a.TxQueue, a.RxQueue, a.Uid, a.Inode, a.LPort, a.RPort,
+		)
+		writeAddrs(bw, a.LAddrs, a.PrimaryLAddr)
+		bw.WriteString("<-> ")
+		writeAddrs(bw, a.RAddrs, a.PrimaryRAddr)
+		for i, name := range layout.trailing {
+			if i == 0 {
+				bw.WriteByte('\t')
+			} else {
+				bw.WriteByte(' ')
+			}
+			bw.WriteString(assocTrailingFormats[name](a))
+		}
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+// FormatAssocs returns the assocs in the format of `/proc/net/sctp/assocs`. See WriteAssocs for the details.
+func FormatAssocs(assocs []*Assoc) string {
+	buf := &bytes.Buffer{}
+	_ = WriteAssocs(buf, assocs) // writing to bytes.Buffer never fails
+	return buf.String()
+}
+
+// WriteEPS writes the EPS records in the format of `/proc/net/sctp/eps`, including the header line.
+// ParseEPS parses the output back into the same records.
+//
+// The output is byte-compatible with the kernel's one, except that the ENDPT and SOCK columns
+// are printed without the leading zeros of the kernel's hashed pointers.
+func WriteEPS(w io.Writer, epses []*EPS) error {
+	// implementation memo:
+	// https://github.com/torvalds/linux/blob/dcc0b49040c70ad827a7f3d58a21b01fdb14e749/net/sctp/proc.c#L179
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, " %s\n", epsHeader)
+	for _, e := range epses {
+		fmt.Fprintf(bw, "%8x %8x %-3d %-3d %-4d %-5d %5d %5d ",
+			e.Endpt, e.Sock, e.Sty, e.Sst, e.Hbkt, e.LPort, e.Uid, e.Inode,
+		)
+		writeAddrs(bw, e.LAddrs, "")
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+// FormatEPS returns the EPS records in the format of `/proc/net/sctp/eps`. See WriteEPS for the details.
+func FormatEPS(epses []*EPS) string {
+	buf := &bytes.Buffer{}
+	_ = WriteEPS(buf, epses) // writing to bytes.Buffer never fails
+	return buf.String()
+}
+
+// WriteRemaddr writes the remaddr records in the format of `/proc/net/sctp/remaddr`, including the header line.
+// ParseRemaddr parses the output back into the same records. The output is byte-compatible with the kernel's one.
+func WriteRemaddr(w io.Writer, remaddrs []*Remaddr) error {
+	// implementation memo:
+	// https://github.com/torvalds/linux/blob/dcc0b49040c70ad827a7f3d58a21b01fdb14e749/net/sctp/proc.c#L339
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%s\n", remaddrHeader)
+	for _, r := range remaddrs {
+		fmt.Fprintf(bw, "%s  %d %d %d %d %d %d %d\n",
+			r.Addr, r.AssocID, r.HbAct, r.RTO, r.MaxPathRtx, r.RemAddrRtx, r.Start, r.State,
+		)
+	}
+	return bw.Flush()
+}
+
+// FormatRemaddr returns the remaddr records in the format of `/proc/net/sctp/remaddr`. See WriteRemaddr for the details.
+func FormatRemaddr(remaddrs []*Remaddr) string {
+	buf := &bytes.Buffer{}
+	_ = WriteRemaddr(buf, remaddrs) // writing to bytes.Buffer never fails
+	return buf.String()
+}
+
+// writeAddrs writes the addresses, each followed by a space. The primary address is marked with `*`.
+func writeAddrs(bw *bufio.Writer, addrs []string, primary string) {
+	for _, addr := range addrs {
+		if primary != "" && addr == primary {
+			bw.WriteByte('*')
+		}
+		bw.WriteString(addr)
+		bw.WriteByte(' ')
+	}
+}
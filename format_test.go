@@ -0,0 +1,109 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// the contents as the kernel prints them; note the tab before the HBINT column.
+const (
+	kernelAssocs = " ASSOC     SOCK   STY SST ST HBKT ASSOC-ID TX_QUEUE RX_QUEUE UID INODE LPORT RPORT LADDRS <-> RADDRS HBINT INS OUTS MAXRT T1X T2X RTXC wmema wmemq sndbuf rcvbuf\n" +
+		"       0        0 2   1   3  0      60        0      496       0 188897 12345 54321  127.0.0.1 <-> *127.0.0.2 \t   30000 65535 65535   10    0    0        0        1        0   212992   212992\n" +
+		"       0        0 0   10  3  0       2      128        0    1000 189472 3868  50000  *10.0.0.1 10.0.0.5 <-> *10.0.0.2 10.0.0.3 2001:0db8:0000:0000:0000:0000:0000:0001 \t   30000    10    10   10    1    2       12     1280     1280   212992   212992\n"
+	kernelEPS = " ENDPT     SOCK   STY SST HBKT LPORT   UID INODE LADDRS\n" +
+		"       0        0 2   10  24   12345     0 227065 127.0.0.1 \n" +
+		"       0        0 0   10  16   3868   1000 232851 10.0.0.1 10.0.0.5 \n"
+	kernelRemaddr = "ADDR ASSOC_ID HB_ACT RTO MAX_PATH_RTX REM_ADDR_RTX START STATE\n" +
+		"127.0.0.2  60 1 3000 5 0 0 2\n" +
+		"10.0.0.3  2 0 1000 5 3 0 0\n"
+)
+
+func TestFormatAssocs_RoundTrip(t *testing.T) {
+	assocs, err := ParseAssocs(bufio.NewScanner(strings.NewReader(kernelAssocs)))
+	assert.NoError(t, err)
+
+	formatted := FormatAssocs(assocs)
+	assert.Equal(t, kernelAssocs, formatted)
+
+	reparsed, err := ParseAssocs(bufio.NewScanner(strings.NewReader(formatted)))
+	assert.NoError(t, err)
+	assert.Equal(t, assocs, reparsed)
+}
+
+func TestFormatAssocs_OlderLayout(t *testing.T) {
+	input := " ASSOC     SOCK   STY SST ST HBKT ASSOC-ID TX_QUEUE RX_QUEUE UID INODE LPORT RPORT LADDRS <-> RADDRS HBINT INS OUTS MAXRT T1X T2X RTXC\n" +
+		"       0        0 2   1   3  0      60        0      496       0 188897 12345 54321  127.0.0.1 <-> *127.0.0.2 \t   30000 65535 65535   10    0    0        0\n"
+
+	assocs, err := ParseAssocs(bufio.NewScanner(strings.NewReader(input)))
+	assert.NoError(t, err)
+
+	formatted := FormatAssocs(assocs)
+	assert.Equal(t, input, formatted)
+
+	reparsed, err := ParseAssocs(bufio.NewScanner(strings.NewReader(formatted)))
+	assert.NoError(t, err)
+	assert.Equal(t, assocs, reparsed)
+}
+
+func TestFormatAssocs_Synthetic(t *testing.T) {
+	assocs := []*Assoc{
+		{
+			Assoc:        0xffff8881,
+			Sock:         0xffff8882,
+			Sty:          SocketTypeOneToOne,
+			Sst:          SocketStateEstablished,
+			St:           AssocStateEstablished,
+			AssocId:      7,
+			LPort:        3868,
+			RPort:        40000,
+			LAddrs:       []string{"10.0.0.1"},
+			RAddrs:       []string{"10.0.0.2", "10.0.0.3"},
+			PrimaryRAddr: "10.0.0.3",
+			Rtxc:         5,
+		},
+	}
+
+	reparsed, err := ParseAssocs(bufio.NewScanner(strings.NewReader(FormatAssocs(assocs))))
+	assert.NoError(t, err)
+	assert.Len(t, reparsed, 1)
+	assert.EqualValues(t, 0xffff8881, reparsed[0].Assoc)
+	assert.Equal(t, AssocStateEstablished, reparsed[0].St)
+	assert.Equal(t, []string{"10.0.0.2", "10.0.0.3"}, reparsed[0].RAddrs)
+	assert.Equal(t, "10.0.0.3", reparsed[0].PrimaryRAddr)
+	assert.Equal(t, "", reparsed[0].PrimaryLAddr)
+	assert.EqualValues(t, 5, reparsed[0].Rtxc)
+}
+
+func TestFormatEPS_RoundTrip(t *testing.T) {
+	epses, err := ParseEPS(bufio.NewScanner(strings.NewReader(kernelEPS)))
+	assert.NoError(t, err)
+
+	formatted := FormatEPS(epses)
+	assert.Equal(t, kernelEPS, formatted)
+
+	reparsed, err := ParseEPS(bufio.NewScanner(strings.NewReader(formatted)))
+	assert.NoError(t, err)
+	assert.Equal(t, epses, reparsed)
+}
+
+func TestFormatRemaddr_RoundTrip(t *testing.T) {
+	remaddrs, err := ParseRemaddr(bufio.NewScanner(strings.NewReader(kernelRemaddr)))
+	assert.NoError(t, err)
+
+	formatted := FormatRemaddr(remaddrs)
+	assert.Equal(t, kernelRemaddr, formatted)
+
+	reparsed, err := ParseRemaddr(bufio.NewScanner(strings.NewReader(formatted)))
+	assert.NoError(t, err)
+	assert.Equal(t, remaddrs, reparsed)
+}
+
+func TestWriteAssocs_Empty(t *testing.T) {
+	buf := &bytes.Buffer{}
+	assert.NoError(t, WriteAssocs(buf, nil))
+	assert.Equal(t, " "+assocsHeaders[0]+"\n", buf.String())
+}
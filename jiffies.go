@@ -0,0 +1,24 @@
+package parser
+
+import "time"
+
+// DefaultHZ is the kernel's CONFIG_HZ that is assumed when it is unknown; i.e. the number of jiffies per second.
+// Some distributions build the kernel with HZ=250 or HZ=100, so the values in jiffies (e.g. Remaddr.RTO) have to be converted with the actual one.
+const DefaultHZ = 1000
+
+// JiffiesToDuration converts the jiffies into a duration with the kernel's CONFIG_HZ. hz less than 1 is treated as DefaultHZ.
+func JiffiesToDuration(jiffies uint64, hz int) time.Duration {
+	if hz < 1 {
+		hz = DefaultHZ
+	}
+	return time.Duration(jiffies) * time.Second / time.Duration(hz)
+}
+
+// DurationToJiffies converts the duration into jiffies with the kernel's CONFIG_HZ, rounding down as the kernel does.
+// hz less than 1 is treated as DefaultHZ.
+func DurationToJiffies(d time.Duration, hz int) uint64 {
+	if hz < 1 {
+		hz = DefaultHZ
+	}
+	return uint64(d * time.Duration(hz) / time.Second)
+}
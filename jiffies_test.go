@@ -0,0 +1,22 @@
+package parser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJiffiesToDuration(t *testing.T) {
+	assert.Equal(t, 3*time.Second, JiffiesToDuration(3000, 1000))
+	assert.Equal(t, 3*time.Second, JiffiesToDuration(750, 250))
+	assert.Equal(t, 3*time.Second, JiffiesToDuration(300, 100))
+	assert.Equal(t, 3*time.Second, JiffiesToDuration(3000, 0))
+}
+
+func TestDurationToJiffies(t *testing.T) {
+	assert.EqualValues(t, 3000, DurationToJiffies(3*time.Second, 1000))
+	assert.EqualValues(t, 750, DurationToJiffies(3*time.Second, 250))
+	assert.EqualValues(t, 300, DurationToJiffies(3*time.Second, 100))
+	assert.EqualValues(t, 3000, DurationToJiffies(3*time.Second, 0))
+}
@@ -0,0 +1,126 @@
+package parser
+
+import (
+	"errors"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// NetNS is a network namespace, which is identified by the inode of `<pid>/ns/net`.
+type NetNS struct {
+	Inode uint64
+	// PIDs are the processes in the namespace, in ascending order.
+	PIDs []int
+}
+
+// ForPID returns a ProcFS that reads the SCTP files (e.g. ReadAssocs and ReadSnapshot) of the network namespace
+// of the process; i.e. the files under `<pid>/net/sctp`.
+// The other files, e.g. of ReadSysctls and ReadSocketOwners, are read in the same way as the original ProcFS.
+func (fs ProcFS) ForPID(pid int) ProcFS {
+	return ProcFS{root: fs.root, pid: pid}
+}
+
+// PID returns the process whose network namespace the SCTP files are read from. It returns 0 if ProcFS is not for a process.
+func (fs ProcFS) PID() int {
+	return fs.pid
+}
+
+// ReadNetNamespaces enumerates the distinct network namespaces of all the processes, in ascending order of the first PIDs.
+//
+// The processes whose namespaces can't be read (e.g. because of the lack of the permission, or the process has exited during the scan) are skipped.
+func (fs ProcFS) ReadNetNamespaces() ([]*NetNS, error) {
+	entries, err := os.ReadDir(fs.root)
+	if err != nil {
+		return nil, err
+	}
+
+	pids := make([]int, 0, len(entries))
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil || !entry.IsDir() {
+			continue // not a process
+		}
+		pids = append(pids, pid)
+	}
+	sort.Ints(pids)
+
+	byInode := make(map[uint64]*NetNS)
+	namespaces := make([]*NetNS, 0)
+	for _, pid := range pids {
+		inode, ok := fs.readNetNSInode(pid)
+		if !ok {
+			continue
+		}
+
+		ns, ok := byInode[inode]
+		if !ok {
+			ns = &NetNS{Inode: inode}
+			byInode[inode] = ns
+			namespaces = append(namespaces, ns)
+		}
+		ns.PIDs = append(ns.PIDs, pid)
+	}
+
+	return namespaces, nil
+}
+
+// ReadNetNSSnapshots reads a snapshot (see ReadSnapshot) of every network namespace, keyed by the inode of the namespace.
+//
+// The files of a namespace are read through the first process in it. If the process has exited, the next one is used;
+// the namespace is omitted if all of its processes have exited.
+func (fs ProcFS) ReadNetNSSnapshots(maxAttempts int) (map[uint64]*Snapshot, error) {
+	namespaces, err := fs.ReadNetNamespaces()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := make(map[uint64]*Snapshot, len(namespaces))
+	for _, ns := range namespaces {
+		for _, pid := range ns.PIDs {
+			snapshot, err := fs.ForPID(pid).ReadSnapshot(maxAttempts)
+			if err != nil {
+				if fs.isExited(pid) {
+					continue
+				}
+				return nil, err
+			}
+			snapshots[ns.Inode] = snapshot
+			break
+		}
+	}
+
+	return snapshots, nil
+}
+
+// ReadNetNamespaces enumerates the distinct network namespaces of all the processes under `/proc`.
+func ReadNetNamespaces() ([]*NetNS, error) {
+	return NewProcFS(DefaultProcRoot).ReadNetNamespaces()
+}
+
+// ReadNetNSSnapshots reads a snapshot of every network namespace under `/proc` with DefaultSnapshotAttempts attempts.
+func ReadNetNSSnapshots() (map[uint64]*Snapshot, error) {
+	return NewProcFS(DefaultProcRoot).ReadNetNSSnapshots(DefaultSnapshotAttempts)
+}
+
+// readNetNSInode reads the inode from `<pid>/ns/net`, which is a symlink to `net:[<inode>]`.
+func (fs ProcFS) readNetNSInode(pid int) (uint64, bool) {
+	target, err := os.Readlink(fs.Path(strconv.Itoa(pid), "ns", "net"))
+	if err != nil {
+		return 0, false
+	}
+	if !strings.HasPrefix(target, "net:[") || !strings.HasSuffix(target, "]") {
+		return 0, false
+	}
+	inode, err := strconv.ParseUint(target[len("net:["):len(target)-1], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return inode, true
+}
+
+func (fs ProcFS) isExited(pid int) bool {
+	_, err := os.Stat(fs.Path(strconv.Itoa(pid)))
+	return errors.Is(err, os.ErrNotExist)
+}
@@ -0,0 +1,102 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// makeFakeNetNS makes a process in the network namespace, whose SCTP files have the assocs.
+func makeFakeNetNS(t *testing.T, root string, pid string, inode string, assocs []*Assoc, remaddrs []*Remaddr) {
+	t.Helper()
+	writeProcFiles(t, root, map[string]string{
+		filepath.Join(pid, "net", "sctp", "assocs"):  FormatAssocs(assocs),
+		filepath.Join(pid, "net", "sctp", "remaddr"): FormatRemaddr(remaddrs),
+		filepath.Join(pid, "net", "sctp", "eps"):     FormatEPS(nil),
+	})
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, pid, "ns"), 0o755))
+	assert.NoError(t, os.Symlink("net:["+inode+"]", filepath.Join(root, pid, "ns", "net")))
+}
+
+func TestProcFS_ForPID(t *testing.T) {
+	root := t.TempDir()
+	writeProcFiles(t, root, map[string]string{
+		"net/sctp/assocs": FormatAssocs([]*Assoc{{AssocId: 1, LAddrs: []string{"10.0.0.1"}, RAddrs: []string{"10.0.0.2"}}}),
+	})
+	makeFakeNetNS(t, root, "100", "4026532000", []*Assoc{
+		{AssocId: 2, LAddrs: []string{"10.1.0.1"}, RAddrs: []string{"10.1.0.2"}},
+	}, nil)
+
+	fs := NewProcFS(root)
+	assocs, err := fs.ReadAssocs()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, assocs[0].AssocId)
+
+	pidFS := fs.ForPID(100)
+	assert.Equal(t, 100, pidFS.PID())
+	assert.Equal(t, root, pidFS.Root())
+	assocs, err = pidFS.ReadAssocs()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, assocs[0].AssocId)
+
+	_, err = fs.ForPID(200).ReadAssocs()
+	assert.ErrorIs(t, err, ErrSCTPNotAvailable)
+}
+
+func TestProcFS_ReadNetNamespaces(t *testing.T) {
+	root := t.TempDir()
+	makeFakeNetNS(t, root, "1", "4026531840", nil, nil)
+	makeFakeNetNS(t, root, "20", "4026532000", nil, nil)
+	makeFakeNetNS(t, root, "3", "4026531840", nil, nil)
+	makeFakeNetNS(t, root, "100", "4026532000", nil, nil)
+	makeFakeProcess(t, root, "50", "kthread", "", nil) // no namespace link
+	writeProcFiles(t, root, map[string]string{"self/ns/net": "", "uptime": ""})
+
+	namespaces, err := NewProcFS(root).ReadNetNamespaces()
+	assert.NoError(t, err)
+	assert.Equal(t, []*NetNS{
+		{Inode: 4026531840, PIDs: []int{1, 3}},
+		{Inode: 4026532000, PIDs: []int{20, 100}},
+	}, namespaces)
+}
+
+func TestProcFS_ReadNetNSSnapshots(t *testing.T) {
+	root := t.TempDir()
+	makeFakeNetNS(t, root, "1", "4026531840", []*Assoc{
+		{AssocId: 1, LAddrs: []string{"10.0.0.1"}, RAddrs: []string{"10.0.0.2"}, PrimaryRAddr: "10.0.0.2"},
+	}, []*Remaddr{
+		{Addr: "10.0.0.2", AssocID: 1},
+	})
+	makeFakeNetNS(t, root, "20", "4026532000", []*Assoc{
+		{AssocId: 1, LAddrs: []string{"10.1.0.1"}, RAddrs: []string{"10.1.0.2"}, PrimaryRAddr: "10.1.0.2"},
+		{AssocId: 2, LAddrs: []string{"10.1.0.1"}, RAddrs: []string{"10.1.0.3"}, PrimaryRAddr: "10.1.0.3"},
+	}, []*Remaddr{
+		{Addr: "10.1.0.2", AssocID: 1},
+		{Addr: "10.1.0.3", AssocID: 2},
+	})
+
+	snapshots, err := NewProcFS(root).ReadNetNSSnapshots(DefaultSnapshotAttempts)
+	assert.NoError(t, err)
+	assert.Len(t, snapshots, 2)
+
+	host := snapshots[4026531840]
+	assert.True(t, host.Consistent)
+	assert.Len(t, host.Associations, 1)
+	assert.Equal(t, []string{"10.0.0.2"}, host.Associations[0].Assoc.RAddrs)
+
+	pod := snapshots[4026532000]
+	assert.True(t, pod.Consistent)
+	assert.Len(t, pod.Associations, 2)
+	assert.Equal(t, "10.1.0.3", pod.Associations[1].PrimaryPath().Addr)
+}
+
+func TestProcFS_ReadNetNSSnapshots_WithoutSCTP(t *testing.T) {
+	root := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "1", "ns"), 0o755))
+	assert.NoError(t, os.Symlink("net:[4026531840]", filepath.Join(root, "1", "ns", "net")))
+
+	_, err := NewProcFS(root).ReadNetNSSnapshots(DefaultSnapshotAttempts)
+	assert.ErrorIs(t, err, ErrSCTPNotAvailable)
+}
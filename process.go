@@ -0,0 +1,114 @@
+package parser
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Process represents a process that owns SCTP sockets.
+type Process struct {
+	PID     int      `json:"pid" yaml:"pid"`
+	Comm    string   `json:"comm" yaml:"comm"`
+	Cmdline []string `json:"cmdline" yaml:"cmdline"`
+}
+
+// SocketOwners maps the socket inodes to the processes that have the sockets open.
+type SocketOwners map[uint64][]*Process
+
+// ReadSocketOwners scans the file descriptors of all the processes (i.e. `<pid>/fd/*` symlinks that point to `socket:[<inode>]`)
+// and returns the owners of the sockets, like `ss -p` does.
+//
+// The processes whose file descriptors can't be read (e.g. because of the lack of the permission, or the process has exited during the scan) are skipped.
+func (fs ProcFS) ReadSocketOwners() (SocketOwners, error) {
+	entries, err := os.ReadDir(fs.root)
+	if err != nil {
+		return nil, err
+	}
+
+	owners := make(SocketOwners)
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil || !entry.IsDir() {
+			continue // not a process
+		}
+
+		inodes := fs.readSocketInodes(entry.Name())
+		if len(inodes) <= 0 {
+			continue
+		}
+
+		process := &Process{
+			PID:     pid,
+			Comm:    fs.readComm(entry.Name()),
+			Cmdline: fs.readCmdline(entry.Name()),
+		}
+		for _, inode := range inodes {
+			owners[inode] = append(owners[inode], process)
+		}
+	}
+
+	return owners, nil
+}
+
+// ReadSocketOwners scans the file descriptors of all the processes under `/proc`.
+func ReadSocketOwners() (SocketOwners, error) {
+	return NewProcFS(DefaultProcRoot).ReadSocketOwners()
+}
+
+// AnnotateAssocs sets the owners of the sockets to Assoc.Owners.
+func (o SocketOwners) AnnotateAssocs(assocs []*Assoc) {
+	for _, assoc := range assocs {
+		assoc.Owners = o[assoc.Inode]
+	}
+}
+
+// AnnotateEPS sets the owners of the sockets to EPS.Owners.
+func (o SocketOwners) AnnotateEPS(eps []*EPS) {
+	for _, e := range eps {
+		e.Owners = o[e.Inode]
+	}
+}
+
+// readSocketInodes returns the distinct inodes of the sockets that the process has open.
+func (fs ProcFS) readSocketInodes(pid string) []uint64 {
+	fds, err := os.ReadDir(fs.Path(pid, "fd"))
+	if err != nil {
+		return nil
+	}
+
+	seen := make(map[uint64]bool)
+	inodes := make([]uint64, 0)
+	for _, fd := range fds {
+		target, err := os.Readlink(fs.Path(pid, "fd", fd.Name()))
+		if err != nil {
+			continue
+		}
+		if !strings.HasPrefix(target, "socket:[") || !strings.HasSuffix(target, "]") {
+			continue
+		}
+		inode, err := strconv.ParseUint(target[len("socket:["):len(target)-1], 10, 64)
+		if err != nil || seen[inode] {
+			continue
+		}
+		seen[inode] = true
+		inodes = append(inodes, inode)
+	}
+	return inodes
+}
+
+func (fs ProcFS) readComm(pid string) string {
+	comm, err := os.ReadFile(fs.Path(pid, "comm"))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(string(comm), "\n")
+}
+
+func (fs ProcFS) readCmdline(pid string) []string {
+	cmdline, err := os.ReadFile(fs.Path(pid, "cmdline"))
+	if err != nil || len(cmdline) <= 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(string(cmdline), "\x00"), "\x00")
+}
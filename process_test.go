@@ -0,0 +1,62 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// makeFakeProcess makes `<pid>` directory of a fake proc filesystem; fds maps the fd numbers to the symlink targets.
+func makeFakeProcess(t *testing.T, root string, pid string, comm string, cmdline string, fds map[string]string) {
+	t.Helper()
+	writeProcFiles(t, root, map[string]string{
+		filepath.Join(pid, "comm"):    comm + "\n",
+		filepath.Join(pid, "cmdline"): cmdline,
+	})
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, pid, "fd"), 0o755))
+	for fd, target := range fds {
+		assert.NoError(t, os.Symlink(target, filepath.Join(root, pid, "fd", fd)))
+	}
+}
+
+func TestProcFS_ReadSocketOwners(t *testing.T) {
+	root := t.TempDir()
+	makeFakeProcess(t, root, "100", "diameterd", "/usr/bin/diameterd\x00--port\x003868\x00", map[string]string{
+		"0": "/dev/null",
+		"3": "socket:[1000]",
+		"4": "socket:[1001]",
+		"5": "socket:[1001]",
+	})
+	makeFakeProcess(t, root, "200", "worker", "worker\x00", map[string]string{
+		"7": "socket:[1001]",
+		"8": "pipe:[5555]",
+	})
+	makeFakeProcess(t, root, "300", "idle", "", map[string]string{
+		"0": "/dev/null",
+	})
+	writeProcFiles(t, root, map[string]string{
+		"self/comm":       "self\n",
+		"net/sctp/assocs": "",
+	})
+
+	owners, err := NewProcFS(root).ReadSocketOwners()
+	assert.NoError(t, err)
+
+	diameterd := &Process{PID: 100, Comm: "diameterd", Cmdline: []string{"/usr/bin/diameterd", "--port", "3868"}}
+	worker := &Process{PID: 200, Comm: "worker", Cmdline: []string{"worker"}}
+	assert.Equal(t, SocketOwners{
+		1000: {diameterd},
+		1001: {diameterd, worker},
+	}, owners)
+
+	assocs := []*Assoc{{Inode: 1001}, {Inode: 9999}}
+	owners.AnnotateAssocs(assocs)
+	assert.Equal(t, []*Process{diameterd, worker}, assocs[0].Owners)
+	assert.Nil(t, assocs[1].Owners)
+
+	eps := []*EPS{{Inode: 1000}}
+	owners.AnnotateEPS(eps)
+	assert.Equal(t, []*Process{diameterd}, eps[0].Owners)
+}
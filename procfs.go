@@ -0,0 +1,172 @@
+package parser
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+var (
+	ErrSCTPNotAvailable = errors.New("SCTP is not available; the sctp kernel module may not be loaded")
+)
+
+// DefaultProcRoot is the mount point of the proc filesystem on a usual host.
+const DefaultProcRoot = "/proc"
+
+// ProcFS reads SCTP files from the proc filesystem.
+type ProcFS struct {
+	root string
+	// pid is the process whose network namespace the `net` files are read from; 0 means the namespace of `<root>/net`.
+	pid int
+}
+
+// NewProcFS returns a new ProcFS that reads files under the root; e.g. `/host/proc` in a container that mounts the host's proc filesystem.
+func NewProcFS(root string) ProcFS {
+	return ProcFS{root: root}
+}
+
+// Root returns the mount point of the proc filesystem.
+func (fs ProcFS) Root() string {
+	return fs.root
+}
+
+// Path returns the path of the file under the proc filesystem.
+func (fs ProcFS) Path(elem ...string) string {
+	return filepath.Join(append([]string{fs.root}, elem...)...)
+}
+
+// ReadAssocs reads and parses `net/sctp/assocs` file.
+func (fs ProcFS) ReadAssocs() ([]*Assoc, error) {
+	return readSCTPFile(fs, "assocs", ParseAssocs)
+}
+
+// ReadEPS reads and parses `net/sctp/eps` file.
+func (fs ProcFS) ReadEPS() ([]*EPS, error) {
+	return readSCTPFile(fs, "eps", ParseEPS)
+}
+
+// ReadRemaddr reads and parses `net/sctp/remaddr` file.
+func (fs ProcFS) ReadRemaddr() ([]*Remaddr, error) {
+	return readSCTPFile(fs, "remaddr", ParseRemaddr)
+}
+
+// ReadSNMP reads and parses `net/sctp/snmp` file.
+func (fs ProcFS) ReadSNMP() (*SNMP, error) {
+	f, err := fs.openSCTPFile("snmp")
+	if err != nil {
+		return nil, err
+	}
+	return parseSNMPFile(f)
+}
+
+// ReadSysctls reads and parses the files under `sys/net/sctp` directory.
+func (fs ProcFS) ReadSysctls() (*Sysctls, error) {
+	dir := fs.Path("sys", "net", "sctp")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%s: %w", dir, ErrSCTPNotAvailable)
+		}
+		return nil, err
+	}
+
+	values := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		value, err := os.ReadFile(fs.Path("sys", "net", "sctp", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		values[entry.Name()] = string(value)
+	}
+
+	sysctls, err := ParseSysctls(values)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", dir, err)
+	}
+	return sysctls, nil
+}
+
+// netPath returns the path of the file under `net` directory, or `<pid>/net` if the ProcFS is for a process.
+func (fs ProcFS) netPath(elem ...string) string {
+	if fs.pid > 0 {
+		return fs.Path(append([]string{strconv.Itoa(fs.pid), "net"}, elem...)...)
+	}
+	return fs.Path(append([]string{"net"}, elem...)...)
+}
+
+// openSCTPFile opens the file under `net/sctp`.
+// It returns ErrSCTPNotAvailable if `net/sctp` directory doesn't exist.
+func (fs ProcFS) openSCTPFile(name string) (*os.File, error) {
+	f, err := os.Open(fs.netPath("sctp", name))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			dir := fs.netPath("sctp")
+			if _, statErr := os.Stat(dir); errors.Is(statErr, os.ErrNotExist) {
+				return nil, fmt.Errorf("%s: %w", dir, ErrSCTPNotAvailable)
+			}
+		}
+		return nil, err
+	}
+	return f, nil
+}
+
+func readSCTPFile[T any](fs ProcFS, name string, parse func(*bufio.Scanner, ...bool) ([]T, error)) ([]T, error) {
+	f, err := fs.openSCTPFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return parseRecordsFile(f, parse)
+}
+
+// parseRecordsFile parses the records of the file, and closes it.
+func parseRecordsFile[T any](f *os.File, parse func(*bufio.Scanner, ...bool) ([]T, error)) ([]T, error) {
+	defer f.Close()
+
+	records, err := parse(bufio.NewScanner(f))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", f.Name(), err)
+	}
+	return records, nil
+}
+
+// parseSNMPFile parses the SNMP counters of the file, and closes it.
+func parseSNMPFile(f *os.File) (*SNMP, error) {
+	defer f.Close()
+
+	snmp, err := ParseSNMP(bufio.NewScanner(f))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", f.Name(), err)
+	}
+	return snmp, nil
+}
+
+// ReadAssocs reads and parses `/proc/net/sctp/assocs` file.
+func ReadAssocs() ([]*Assoc, error) {
+	return NewProcFS(DefaultProcRoot).ReadAssocs()
+}
+
+// ReadEPS reads and parses `/proc/net/sctp/eps` file.
+func ReadEPS() ([]*EPS, error) {
+	return NewProcFS(DefaultProcRoot).ReadEPS()
+}
+
+// ReadRemaddr reads and parses `/proc/net/sctp/remaddr` file.
+func ReadRemaddr() ([]*Remaddr, error) {
+	return NewProcFS(DefaultProcRoot).ReadRemaddr()
+}
+
+// ReadSNMP reads and parses `/proc/net/sctp/snmp` file.
+func ReadSNMP() (*SNMP, error) {
+	return NewProcFS(DefaultProcRoot).ReadSNMP()
+}
+
+// ReadSysctls reads and parses the files under `/proc/sys/net/sctp` directory.
+func ReadSysctls() (*Sysctls, error) {
+	return NewProcFS(DefaultProcRoot).ReadSysctls()
+}
@@ -0,0 +1,106 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeProcFiles writes the files under the root; the keys of files are the paths relative to the root.
+func writeProcFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, contents := range files {
+		path := filepath.Join(root, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.NoError(t, os.WriteFile(path, []byte(contents), 0o644))
+	}
+}
+
+func TestProcFS(t *testing.T) {
+	root := t.TempDir()
+	writeProcFiles(t, root, map[string]string{
+		"net/sctp/assocs": ` ASSOC     SOCK   STY SST ST HBKT ASSOC-ID TX_QUEUE RX_QUEUE UID INODE LPORT RPORT LADDRS <-> RADDRS HBINT INS OUTS MAXRT T1X T2X RTXC wmema wmemq sndbuf rcvbuf
+       0        0 2   1   3  0      60        0      496       0 188897 12345 54321  127.0.0.1 <-> *127.0.0.2 	   30000 65535 65535   10    0    0        0        1        0   212992   212992
+`,
+		"net/sctp/eps": ` ENDPT     SOCK   STY SST HBKT LPORT   UID INODE LADDRS
+       0        0 2   10  24   12345     0 227065 127.0.0.1 
+`,
+		"net/sctp/remaddr": `ADDR ASSOC_ID HB_ACT RTO MAX_PATH_RTX REM_ADDR_RTX START STATE
+127.0.0.2  60 1 1000 5 0 0 2
+`,
+		"net/sctp/snmp": `SctpCurrEstab                   	1
+SctpActiveEstabs                	1
+`,
+	})
+
+	fs := NewProcFS(root)
+	assert.Equal(t, root, fs.Root())
+
+	assocs, err := fs.ReadAssocs()
+	assert.NoError(t, err)
+	assert.Len(t, assocs, 1)
+	assert.EqualValues(t, 60, assocs[0].AssocId)
+
+	eps, err := fs.ReadEPS()
+	assert.NoError(t, err)
+	assert.Len(t, eps, 1)
+	assert.EqualValues(t, 227065, eps[0].Inode)
+
+	remaddrs, err := fs.ReadRemaddr()
+	assert.NoError(t, err)
+	assert.Len(t, remaddrs, 1)
+	assert.Equal(t, "127.0.0.2", remaddrs[0].Addr)
+
+	snmp, err := fs.ReadSNMP()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, snmp.CurrEstab)
+}
+
+func TestProcFS_ReadSysctls(t *testing.T) {
+	root := t.TempDir()
+	writeProcFiles(t, root, map[string]string{
+		"sys/net/sctp/rto_min":      "1000\n",
+		"sys/net/sctp/addip_enable": "1\n",
+	})
+
+	sysctls, err := NewProcFS(root).ReadSysctls()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Second, sysctls.RTOMin)
+	assert.True(t, sysctls.AddipEnable)
+
+	_, err = NewProcFS(t.TempDir()).ReadSysctls()
+	assert.ErrorIs(t, err, ErrSCTPNotAvailable)
+}
+
+func TestProcFS_WithoutSCTP(t *testing.T) {
+	root := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "net"), 0o755))
+
+	_, err := NewProcFS(root).ReadAssocs()
+	assert.ErrorIs(t, err, ErrSCTPNotAvailable)
+}
+
+func TestProcFS_WithMissingFile(t *testing.T) {
+	root := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "net", "sctp"), 0o755))
+
+	_, err := NewProcFS(root).ReadEPS()
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	assert.NotErrorIs(t, err, ErrSCTPNotAvailable)
+}
+
+func TestProcFS_WithInvalidContents(t *testing.T) {
+	root := t.TempDir()
+	writeProcFiles(t, root, map[string]string{
+		"net/sctp/remaddr": `ADDR ASSOC_ID HB_ACT RTO MAX_PATH_RTX REM_ADDR_RTX START STATE
+127.0.0.2  x 1 1000 5 0 0 2
+`,
+	})
+
+	_, err := NewProcFS(root).ReadRemaddr()
+	assert.ErrorIs(t, err, ErrInvalidRemaddrFormat)
+	assert.Contains(t, err.Error(), filepath.Join(root, "net", "sctp", "remaddr"))
+}
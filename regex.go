@@ -3,3 +3,14 @@ package parser
 import "regexp"
 
 var spacesRe = regexp.MustCompile("[ \t]+")
+
+var (
+	// containerScopeRe matches the cgroup of a container that is managed by systemd; e.g. `docker-<id>.scope`.
+	// cri-o also names the cgroup `crio-<id>` without systemd.
+	containerScopeRe = regexp.MustCompile(`^(docker|cri-containerd|crio)-([0-9a-f]{64})(?:\.scope)?$`)
+	// containerIDRe matches the cgroup of a container that is managed by cgroupfs; e.g. `/docker/<id>`.
+	containerIDRe = regexp.MustCompile(`^[0-9a-f]{64}$`)
+	// podRe matches the cgroup of a Kubernetes pod; e.g. `pod<uid>` with cgroupfs, `kubepods-besteffort-pod<uid>.slice` with systemd.
+	// The UID is separated with underscores instead of dashes in the systemd slice.
+	podRe = regexp.MustCompile(`^(?:kubepods-(?:besteffort-|burstable-)?)?pod([0-9a-f]{8}[-_][0-9a-f]{4}[-_][0-9a-f]{4}[-_][0-9a-f]{4}[-_][0-9a-f]{12})(?:\.slice)?$`)
+)
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"errors"
 	"fmt"
+	"net/netip"
 	"strconv"
 	"strings"
 )
@@ -13,22 +14,44 @@ var (
 	ErrInvalidRemaddrFormat             = errors.New("invalid Remaddr format")
 )
 
+const remaddrHeader = "ADDR ASSOC_ID HB_ACT RTO MAX_PATH_RTX REM_ADDR_RTX START STATE"
+
+var defaultRemaddrLayout = mustParseColumnLayout(remaddrHeader, nil)
+
+// remaddrColumns maps the header names of remaddr to the functions that set the column value to a Remaddr.
+// Columns that are not in this map are ignored.
+var remaddrColumns = map[string]func(r *Remaddr, v string) error{
+	"ADDR":         func(r *Remaddr, v string) error { r.Addr = v; return nil },
+	"ASSOC_ID":     func(r *Remaddr, v string) (err error) { r.AssocID, err = strconv.ParseInt(v, 10, 64); return },
+	"HB_ACT":       func(r *Remaddr, v string) (err error) { r.HbAct, err = strconv.ParseInt(v, 10, 64); return },
+	"RTO":          func(r *Remaddr, v string) (err error) { r.RTO, err = strconv.ParseUint(v, 10, 64); return },
+	"MAX_PATH_RTX": func(r *Remaddr, v string) (err error) { r.MaxPathRtx, err = strconv.ParseInt(v, 10, 64); return },
+	"REM_ADDR_RTX": func(r *Remaddr, v string) (err error) { r.RemAddrRtx, err = strconv.ParseInt(v, 10, 64); return },
+	"START":        func(r *Remaddr, v string) (err error) { r.Start, err = strconv.ParseInt(v, 10, 64); return },
+	"STATE":        func(r *Remaddr, v string) (err error) { r.State, err = parseIntColumn[TransportState](v); return },
+}
+
 // Remaddr represents the structure of SCTP remaddr.
 type Remaddr struct {
-	Addr       string
-	AssocID    int64
-	HbAct      int64
-	RTO        uint64
-	MaxPathRtx int64
-	RemAddrRtx int64
-	Start      int64
-	State      int64
+	Addr       string         `json:"addr" yaml:"addr"`
+	AssocID    int64          `json:"assoc_id" yaml:"assoc_id"`
+	HbAct      int64          `json:"hb_act" yaml:"hb_act"`
+	RTO        uint64         `json:"rto" yaml:"rto"` // in jiffies, as the kernel prints it; see JiffiesToDuration
+	MaxPathRtx int64          `json:"max_path_rtx" yaml:"max_path_rtx"`
+	RemAddrRtx int64          `json:"rem_addr_rtx" yaml:"rem_addr_rtx"`
+	Start      int64          `json:"start" yaml:"start"`
+	State      TransportState `json:"state" yaml:"state"`
+
+	// AddrIP is the parsed form of Addr.
+	AddrIP netip.Addr `json:"-" yaml:"-"`
 }
 
 // ParseRemaddr parses SCTP remaddr contents; for example the contents of `/proc/net/sctp/remaddr` file.
 //
+// The columns are located according to the header line, so the order of the columns may differ from the example below.
+//
 // - input: the contents of SCTP remaddr
-// - noHeader: this has to be true if the input doesn't have a header line (default: `false`)
+// - noHeader: this has to be true if the input doesn't have a header line (default: `false`). In that case, the columns are expected to be in the order of the example below.
 //
 // example input:
 // ```
@@ -39,70 +62,109 @@ type Remaddr struct {
 // 127.0.0.2  68 1 3000 5 0 0 2
 // ```
 func ParseRemaddr(input *bufio.Scanner, noHeader ...bool) ([]*Remaddr, error) {
-	// implementation memo:
-	// https://github.com/torvalds/linux/blob/dcc0b49040c70ad827a7f3d58a21b01fdb14e749/net/sctp/proc.c#L302
+	scanner := NewRemaddrScanner(input, noHeader...)
 
-	lineNum := 1
-	if len(noHeader) <= 0 || !noHeader[0] {
-		input.Scan() // skip a header line
-		lineNum++
+	remaddrs := make([]*Remaddr, 0)
+	for scanner.Next() {
+		remaddrs = append(remaddrs, scanner.Record())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
 	}
 
-	remaddrs := make([]*Remaddr, 0)
+	return remaddrs, nil
+}
 
-	for input.Scan() {
-		if input.Text() == "" {
-			lineNum++
-			continue
-		}
+// RemaddrScanner reads SCTP remaddr contents one record at a time.
+// This is useful to process a large number of records without holding all of them in memory.
+type RemaddrScanner struct {
+	lineScanner
+	layout *columnLayout
+	record *Remaddr
+}
 
-		leaves := spacesRe.Split(strings.TrimSpace(input.Text()), -1)
-		if len(leaves) < 8 {
-			return nil, fmt.Errorf("at line #%d: %w", lineNum, ErrInsufficientNumberOfRemaddrItems)
-		}
+// NewRemaddrScanner returns a new RemaddrScanner that reads SCTP remaddr contents from the input.
+//
+// - input: the contents of SCTP remaddr
+// - noHeader: this has to be true if the input doesn't have a header line (default: `false`)
+func NewRemaddrScanner(input *bufio.Scanner, noHeader ...bool) *RemaddrScanner {
+	return &RemaddrScanner{
+		lineScanner: newLineScanner(input, noHeader),
+	}
+}
 
-		addr := leaves[0]
-		assocID, err := strconv.ParseInt(leaves[1], 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("ASSOC_ID at line #%d: %w", lineNum, ErrInvalidRemaddrFormat)
-		}
-		hbAct, err := strconv.ParseInt(leaves[2], 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("HB_ACT at line #%d: %w", lineNum, ErrInvalidRemaddrFormat)
-		}
-		rto, err := strconv.ParseUint(leaves[3], 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("RTO at line #%d: %w", lineNum, ErrInvalidRemaddrFormat)
-		}
-		maxPathRtx, err := strconv.ParseInt(leaves[4], 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("MAX_PATH_RTX at line #%d: %w", lineNum, ErrInvalidRemaddrFormat)
-		}
-		remAddrRtx, err := strconv.ParseInt(leaves[5], 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("REM_ADDR_RTX at line #%d: %w", lineNum, ErrInvalidRemaddrFormat)
+// Next advances the scanner to the next remaddr record, which will then be available through the Record method.
+// It returns false when the scan stops, either by reaching the end of the input or an error.
+func (s *RemaddrScanner) Next() bool {
+	s.record = nil
+
+	line, lineNum, ok := s.nextLine()
+	if !ok {
+		return false
+	}
+
+	if s.layout == nil {
+		if s.noHeader {
+			s.layout = defaultRemaddrLayout
+		} else {
+			layout, ok := parseColumnLayout(s.header, nil)
+			if !ok {
+				s.err = fmt.Errorf("header at line #1: %w", ErrInvalidRemaddrFormat)
+				return false
+			}
+			s.layout = layout
 		}
-		start, err := strconv.ParseInt(leaves[6], 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("START at line #%d: %w", lineNum, ErrInvalidRemaddrFormat)
+	}
+
+	remaddr, err := parseRemaddrLine(line, lineNum, s.layout)
+	if err != nil {
+		s.err = err
+		return false
+	}
+	s.record = remaddr
+
+	return true
+}
+
+// Record returns the most recent remaddr record generated by a call to Next.
+func (s *RemaddrScanner) Record() *Remaddr {
+	return s.record
+}
+
+func parseRemaddrLine(line string, lineNum int, layout *columnLayout) (*Remaddr, error) {
+	// implementation memo:
+	// https://github.com/torvalds/linux/blob/dcc0b49040c70ad827a7f3d58a21b01fdb14e749/net/sctp/proc.c#L302
+
+	leaves := spacesRe.Split(strings.TrimSpace(line), -1)
+	if len(leaves) < len(layout.leading) {
+		return nil, fmt.Errorf("at line #%d: %w", lineNum, ErrInsufficientNumberOfRemaddrItems)
+	}
+
+	remaddr := &Remaddr{}
+	for i, name := range layout.leading {
+		if err := setRemaddrColumn(remaddr, name, leaves[i], lineNum); err != nil {
+			return nil, err
 		}
-		state, err := strconv.ParseInt(leaves[7], 10, 64)
+	}
+
+	if remaddr.Addr != "" {
+		addrIP, err := netip.ParseAddr(remaddr.Addr)
 		if err != nil {
-			return nil, fmt.Errorf("STATE at line #%d: %w", lineNum, ErrInvalidRemaddrFormat)
+			return nil, fmt.Errorf("ADDR at line #%d: %s: %w", lineNum, err, ErrInvalidAddress)
 		}
-
-		lineNum++
-		remaddrs = append(remaddrs, &Remaddr{
-			Addr:       addr,
-			AssocID:    assocID,
-			HbAct:      hbAct,
-			RTO:        rto,
-			MaxPathRtx: maxPathRtx,
-			RemAddrRtx: remAddrRtx,
-			Start:      start,
-			State:      state,
-		})
+		remaddr.AddrIP = addrIP
 	}
 
-	return remaddrs, nil
+	return remaddr, nil
+}
+
+func setRemaddrColumn(remaddr *Remaddr, name string, value string, lineNum int) error {
+	set, ok := remaddrColumns[name]
+	if !ok {
+		return nil // unknown column
+	}
+	if err := set(remaddr, value); err != nil {
+		return fmt.Errorf("%s at line #%d: %w", name, lineNum, ErrInvalidRemaddrFormat)
+	}
+	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"fmt"
 	"log"
+	"net/netip"
 	"strings"
 	"testing"
 
@@ -28,6 +29,7 @@ func TestParseRemaddr(t *testing.T) {
 		RemAddrRtx: 0,
 		Start:      0,
 		State:      2,
+		AddrIP:     netip.MustParseAddr("127.0.0.10"),
 	}, eps[0])
 	assert.EqualValues(t, &Remaddr{
 		Addr:       "127.0.0.20",
@@ -38,6 +40,7 @@ func TestParseRemaddr(t *testing.T) {
 		RemAddrRtx: 0,
 		Start:      0,
 		State:      3,
+		AddrIP:     netip.MustParseAddr("127.0.0.20"),
 	}, eps[1])
 	assert.EqualValues(t, &Remaddr{
 		Addr:       "127.0.0.1",
@@ -48,6 +51,7 @@ func TestParseRemaddr(t *testing.T) {
 		RemAddrRtx: 0,
 		Start:      0,
 		State:      2,
+		AddrIP:     netip.MustParseAddr("127.0.0.1"),
 	}, eps[2])
 	assert.EqualValues(t, &Remaddr{
 		Addr:       "127.0.0.2",
@@ -58,6 +62,7 @@ func TestParseRemaddr(t *testing.T) {
 		RemAddrRtx: 0,
 		Start:      0,
 		State:      2,
+		AddrIP:     netip.MustParseAddr("127.0.0.2"),
 	}, eps[3])
 }
 
@@ -80,6 +85,50 @@ func TestParseRemaddr_WithInsufficientItems(t *testing.T) {
 	assert.Contains(t, err.Error(), "at line #2")
 }
 
+func TestParseRemaddr_WithReorderedColumns(t *testing.T) {
+	input := `ADDR ASSOC_ID HB_ACT RTO MAX_PATH_RTX REM_ADDR_RTX STATE START NEWCOL
+127.0.0.10  69 1 1000 5 0 2 0 999
+`
+	remaddrs, err := ParseRemaddr(bufio.NewScanner(strings.NewReader(input)))
+	assert.NoError(t, err)
+	assert.EqualValues(t, &Remaddr{
+		Addr:       "127.0.0.10",
+		AssocID:    69,
+		HbAct:      1,
+		RTO:        1000,
+		MaxPathRtx: 5,
+		RemAddrRtx: 0,
+		Start:      0,
+		State:      2,
+		AddrIP:     netip.MustParseAddr("127.0.0.10"),
+	}, remaddrs[0])
+}
+
+func TestParseRemaddr_WithInvalidAddress(t *testing.T) {
+	input := `ADDR ASSOC_ID HB_ACT RTO MAX_PATH_RTX REM_ADDR_RTX START STATE
+127.0.0.1.1  69 1 1000 5 0 0 2
+`
+	_, err := ParseRemaddr(bufio.NewScanner(strings.NewReader(input)))
+	assert.ErrorIs(t, err, ErrInvalidAddress)
+	assert.Contains(t, err.Error(), "ADDR at line #2")
+}
+
+func TestRemaddrScanner(t *testing.T) {
+	input := `127.0.0.10  69 1 1000 5 0 0 2
+127.0.0.20  69 1 3000 5 0 0 3
+127.0.0.1  68 1 1000 5 0 0 x
+`
+	scanner := NewRemaddrScanner(bufio.NewScanner(strings.NewReader(input)), true)
+
+	assert.True(t, scanner.Next())
+	assert.Equal(t, "127.0.0.10", scanner.Record().Addr)
+	assert.True(t, scanner.Next())
+	assert.Equal(t, "127.0.0.20", scanner.Record().Addr)
+	assert.False(t, scanner.Next())
+	assert.ErrorIs(t, scanner.Err(), ErrInvalidRemaddrFormat)
+	assert.Contains(t, scanner.Err().Error(), "STATE at line #3")
+}
+
 func ExampleParseRemaddr() {
 	input := `ADDR ASSOC_ID HB_ACT RTO MAX_PATH_RTX REM_ADDR_RTX START STATE
 127.0.0.10  69 1 1000 5 0 0 2
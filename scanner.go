@@ -0,0 +1,58 @@
+package parser
+
+import (
+	"bufio"
+)
+
+// lineScanner iterates over the non-empty lines of SCTP proc contents with keeping track of the line number.
+type lineScanner struct {
+	input    *bufio.Scanner
+	noHeader bool
+	started  bool
+	header   string
+	lineNum  int
+	err      error
+}
+
+func newLineScanner(input *bufio.Scanner, noHeader []bool) lineScanner {
+	return lineScanner{
+		input:    input,
+		noHeader: len(noHeader) > 0 && noHeader[0],
+		lineNum:  1,
+	}
+}
+
+// nextLine returns the next non-empty line and its line number.
+// It consumes the header line on the first call unless noHeader is true.
+func (s *lineScanner) nextLine() (string, int, bool) {
+	if s.err != nil {
+		return "", 0, false
+	}
+
+	if !s.started {
+		s.started = true
+		if !s.noHeader {
+			s.input.Scan()
+			s.header = s.input.Text()
+			s.lineNum++
+		}
+	}
+
+	for s.input.Scan() {
+		line := s.input.Text()
+		lineNum := s.lineNum
+		s.lineNum++
+		if line == "" {
+			continue
+		}
+		return line, lineNum, true
+	}
+
+	s.err = s.input.Err()
+	return "", 0, false
+}
+
+// Err returns the first error that was encountered by the scanner.
+func (s *lineScanner) Err() error {
+	return s.err
+}
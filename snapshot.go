@@ -0,0 +1,244 @@
+package parser
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// DefaultSnapshotAttempts is the number of the attempts that ReadSnapshot makes to read a consistent snapshot.
+	DefaultSnapshotAttempts = 3
+	// DefaultSnapshotBackoff is the delay before the second attempt to read a consistent snapshot; it doubles on each further attempt.
+	DefaultSnapshotBackoff = 10 * time.Millisecond
+)
+
+// AssociationView is an SCTP association with the paths to its remote addresses;
+// i.e. an Assoc joined with the Remaddr entries that have its association ID.
+type AssociationView struct {
+	Assoc *Assoc `json:"assoc" yaml:"assoc"`
+	// Paths are the Remaddr entries of the association, in the order of the remaddr contents.
+	Paths []*Remaddr `json:"paths" yaml:"paths"`
+}
+
+// PrimaryPath returns the path to the primary remote address of the association.
+// It returns nil if the primary remote address is unknown or there is no path to it.
+func (v *AssociationView) PrimaryPath() *Remaddr {
+	if v.Assoc.PrimaryRAddr == "" {
+		return nil
+	}
+	return v.Path(v.Assoc.PrimaryRAddr)
+}
+
+// ActivePath returns the path that carries the traffic of the association; i.e. the primary path if it is active,
+// otherwise the first active path. It returns nil if no paths are active.
+//
+// The primary remote address (PrimaryPath) is the configured one, which the kernel keeps even when the path fails;
+// the kernel sends over another active path meanwhile. It picks the most recently heard one, which the proc files don't tell,
+// so this is an approximation if multiple paths other than the primary one are active.
+func (v *AssociationView) ActivePath() *Remaddr {
+	if primary := v.PrimaryPath(); primary != nil && primary.State == TransportStateActive {
+		return primary
+	}
+	for _, path := range v.Paths {
+		if path.State == TransportStateActive {
+			return path
+		}
+	}
+	return nil
+}
+
+// Path returns the path to the remote address. It returns nil if there is no such path.
+func (v *AssociationView) Path(addr string) *Remaddr {
+	for _, path := range v.Paths {
+		if path.Addr == addr {
+			return path
+		}
+	}
+	return nil
+}
+
+// Snapshot is a joined view of SCTP associations and their paths.
+type Snapshot struct {
+	// Associations are the associations with their paths, in the order of the assocs contents.
+	Associations []*AssociationView
+	// Orphans are the Remaddr entries whose associations are not in the assocs contents;
+	// this can happen when an association comes or goes between reading assocs and remaddr.
+	Orphans []*Remaddr
+	// Consistent is whether the assocs and the remaddr entries agree with each other;
+	// i.e. there are no orphans, and each association has exactly the paths to its remote addresses.
+	//
+	// EPS are not taken into account; eps lists only the listening endpoints, so the associations that have been initiated
+	// by the local side (e.g. by connect() without listen()) have no endpoints there.
+	Consistent bool
+
+	// EPS are the endpoints. This is set by ReadSnapshot, and nil for the snapshots of NewSnapshot.
+	EPS []*EPS
+	// Time is when the snapshot has been read. This is set by ReadSnapshot.
+	Time time.Time
+	// Attempts is the number of the attempts that have been made to read the snapshot. This is set by ReadSnapshot.
+	Attempts int
+
+	byID map[int64]*AssociationView
+}
+
+// NewSnapshot joins the assocs and the remaddr entries by the association ID.
+func NewSnapshot(assocs []*Assoc, remaddrs []*Remaddr) *Snapshot {
+	snapshot := &Snapshot{
+		Associations: make([]*AssociationView, 0, len(assocs)),
+		Orphans:      make([]*Remaddr, 0),
+		byID:         make(map[int64]*AssociationView, len(assocs)),
+	}
+
+	for _, assoc := range assocs {
+		view := &AssociationView{
+			Assoc: assoc,
+			Paths: make([]*Remaddr, 0, len(assoc.RAddrs)),
+		}
+		snapshot.Associations = append(snapshot.Associations, view)
+		if _, exists := snapshot.byID[assoc.AssocId]; !exists {
+			snapshot.byID[assoc.AssocId] = view
+		}
+	}
+
+	for _, remaddr := range remaddrs {
+		view, ok := snapshot.byID[remaddr.AssocID]
+		if !ok {
+			snapshot.Orphans = append(snapshot.Orphans, remaddr)
+			continue
+		}
+		view.Paths = append(view.Paths, remaddr)
+	}
+
+	snapshot.Consistent = snapshot.isConsistent()
+
+	return snapshot
+}
+
+func (s *Snapshot) isConsistent() bool {
+	if len(s.Orphans) > 0 {
+		return false
+	}
+
+	for _, view := range s.Associations {
+		if len(view.Paths) != len(view.Assoc.RAddrs) {
+			return false
+		}
+		for _, raddr := range view.Assoc.RAddrs {
+			if view.Path(raddr) == nil {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// Lookup returns the association that has the association ID.
+func (s *Snapshot) Lookup(assocID int64) (*AssociationView, bool) {
+	view, ok := s.byID[assocID]
+	return view, ok
+}
+
+// ReadSnapshot reads assocs, remaddr and eps files, and joins them into a snapshot.
+// See SnapshotReader for the attempts.
+func (fs ProcFS) ReadSnapshot(maxAttempts int) (*Snapshot, error) {
+	return ReadSnapshotFrom(context.Background(), fs, maxAttempts)
+}
+
+// ReadSnapshotFrom reads a snapshot from the source with maxAttempts attempts, DefaultSnapshotBackoff and the system clock.
+// See SnapshotReader for the attempts.
+func ReadSnapshotFrom(ctx context.Context, source Source, maxAttempts int) (*Snapshot, error) {
+	reader := NewSnapshotReader(source)
+	reader.MaxAttempts = maxAttempts
+	return reader.Read(ctx)
+}
+
+// SnapshotReader reads the associations, the paths and the endpoints from the source, and joins them into a snapshot.
+//
+// The records can disagree with each other when associations come and go between reading them;
+// in that case, it waits for the backoff and reads the records again up to MaxAttempts times in total.
+// If no attempts result in a consistent snapshot, it returns the last one with Snapshot.Consistent being false.
+type SnapshotReader struct {
+	// Source is where the records are read from.
+	Source Source
+	// MaxAttempts is the number of the attempts to read a consistent snapshot. Less than 1 is treated as 1.
+	MaxAttempts int
+	// Backoff is the delay before the second attempt; it doubles on each further attempt.
+	Backoff time.Duration
+	// Clock provides the time of the snapshots and the delays of the backoff.
+	Clock Clock
+}
+
+// NewSnapshotReader returns a new SnapshotReader of the source with DefaultSnapshotAttempts, DefaultSnapshotBackoff and the system clock.
+func NewSnapshotReader(source Source) *SnapshotReader {
+	return &SnapshotReader{
+		Source:      source,
+		MaxAttempts: DefaultSnapshotAttempts,
+		Backoff:     DefaultSnapshotBackoff,
+		Clock:       SystemClock{},
+	}
+}
+
+// Read reads a snapshot. It returns the context's error if the context is done while waiting for the backoff.
+func (r *SnapshotReader) Read(ctx context.Context) (*Snapshot, error) {
+	maxAttempts := r.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	var snapshot *Snapshot
+	backoff := r.Backoff
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if attempt > 1 {
+			if err := r.wait(ctx, backoff); err != nil {
+				return nil, err
+			}
+			backoff *= 2
+		}
+
+		now := r.Clock.Now()
+
+		assocs, err := r.Source.Assocs(ctx)
+		if err != nil {
+			return nil, err
+		}
+		remaddrs, err := r.Source.Paths(ctx)
+		if err != nil {
+			return nil, err
+		}
+		eps, err := r.Source.Endpoints(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		snapshot = NewSnapshot(assocs, remaddrs)
+		snapshot.EPS = eps
+		snapshot.Time = now
+		snapshot.Attempts = attempt
+		if snapshot.Consistent {
+			break
+		}
+	}
+
+	return snapshot, nil
+}
+
+func (r *SnapshotReader) wait(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	ticker := r.Clock.NewTicker(d)
+	defer ticker.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-ticker.C():
+		return nil
+	}
+}
+
+// ReadSnapshot reads the files under `/proc/net/sctp` and joins them into a snapshot with DefaultSnapshotAttempts attempts.
+func ReadSnapshot() (*Snapshot, error) {
+	return NewProcFS(DefaultProcRoot).ReadSnapshot(DefaultSnapshotAttempts)
+}
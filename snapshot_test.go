@@ -0,0 +1,232 @@
+package parser
+
+import (
+	"bufio"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSnapshot(t *testing.T) {
+	assocs, err := ParseAssocs(bufio.NewScanner(strings.NewReader(`ASSOC     SOCK   STY SST ST HBKT ASSOC-ID TX_QUEUE RX_QUEUE UID INODE LPORT RPORT LADDRS <-> RADDRS HBINT INS OUTS MAXRT T1X T2X RTXC wmema wmemq sndbuf rcvbuf
+	0        0 2   1   3  0      68        0        0       0 212110 54321 12345  127.0.0.10 127.0.0.20 <-> 127.0.0.1 *127.0.0.2    30000 65535 65535   10    0    0        0        1        0   212992   212992
+	0        0 2   1   3  0      69        0        0       0 212095 12345 54321  127.0.0.1 127.0.0.2 <-> *127.0.0.10 127.0.0.20    30000 65535 65535   10    0    0        0        1        0   212992   212992
+`)))
+	assert.NoError(t, err)
+	remaddrs, err := ParseRemaddr(bufio.NewScanner(strings.NewReader(`ADDR ASSOC_ID HB_ACT RTO MAX_PATH_RTX REM_ADDR_RTX START STATE
+127.0.0.10  69 1 1000 5 0 0 2
+127.0.0.20  69 1 3000 5 0 0 3
+127.0.0.1  68 1 1000 5 0 0 2
+127.0.0.2  68 1 3000 5 0 0 2
+127.0.0.3  70 1 3000 5 0 0 2
+`)))
+	assert.NoError(t, err)
+
+	snapshot := NewSnapshot(assocs, remaddrs)
+
+	assert.Len(t, snapshot.Associations, 2)
+	assert.Equal(t, assocs[0], snapshot.Associations[0].Assoc)
+	assert.Equal(t, []*Remaddr{remaddrs[2], remaddrs[3]}, snapshot.Associations[0].Paths)
+	assert.Equal(t, assocs[1], snapshot.Associations[1].Assoc)
+	assert.Equal(t, []*Remaddr{remaddrs[0], remaddrs[1]}, snapshot.Associations[1].Paths)
+	assert.Equal(t, []*Remaddr{remaddrs[4]}, snapshot.Orphans)
+	assert.False(t, snapshot.Consistent)
+
+	view, ok := snapshot.Lookup(68)
+	assert.True(t, ok)
+	assert.Equal(t, remaddrs[3], view.PrimaryPath())
+	assert.Equal(t, remaddrs[2], view.Path("127.0.0.1"))
+	assert.Nil(t, view.Path("127.0.0.3"))
+
+	view, ok = snapshot.Lookup(69)
+	assert.True(t, ok)
+	assert.Equal(t, remaddrs[0], view.PrimaryPath())
+	assert.Equal(t, TransportStateUnconfirmed, view.Paths[1].State)
+
+	_, ok = snapshot.Lookup(70)
+	assert.False(t, ok)
+}
+
+func TestAssociationView_ActivePath(t *testing.T) {
+	view := &AssociationView{
+		Assoc: &Assoc{AssocId: 1, RAddrs: []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"}, PrimaryRAddr: "10.0.0.2"},
+		Paths: []*Remaddr{
+			{AssocID: 1, Addr: "10.0.0.1", State: TransportStateActive},
+			{AssocID: 1, Addr: "10.0.0.2", State: TransportStateActive},
+			{AssocID: 1, Addr: "10.0.0.3", State: TransportStateActive},
+		},
+	}
+	assert.Equal(t, view.Paths[1], view.ActivePath())
+
+	view.Paths[1].State = TransportStatePF
+	assert.Equal(t, view.Paths[0], view.ActivePath())
+
+	view.Paths[0].State = TransportStateInactive
+	view.Paths[2].State = TransportStateUnconfirmed
+	assert.Nil(t, view.ActivePath())
+}
+
+func TestNewSnapshot_WithoutPrimaryPath(t *testing.T) {
+	snapshot := NewSnapshot([]*Assoc{{AssocId: 1, RAddrs: []string{"127.0.0.1"}}}, nil)
+
+	view, ok := snapshot.Lookup(1)
+	assert.True(t, ok)
+	assert.Empty(t, view.Paths)
+	assert.Nil(t, view.PrimaryPath())
+	assert.Empty(t, snapshot.Orphans)
+	assert.False(t, snapshot.Consistent)
+}
+
+func TestNewSnapshot_Consistency(t *testing.T) {
+	assocs := []*Assoc{
+		{AssocId: 1, RAddrs: []string{"127.0.0.1", "127.0.0.2"}},
+	}
+
+	snapshot := NewSnapshot(assocs, []*Remaddr{
+		{AssocID: 1, Addr: "127.0.0.2"},
+		{AssocID: 1, Addr: "127.0.0.1"},
+	})
+	assert.True(t, snapshot.Consistent)
+
+	snapshot = NewSnapshot(assocs, []*Remaddr{
+		{AssocID: 1, Addr: "127.0.0.1"},
+	})
+	assert.False(t, snapshot.Consistent)
+
+	snapshot = NewSnapshot(assocs, []*Remaddr{
+		{AssocID: 1, Addr: "127.0.0.1"},
+		{AssocID: 1, Addr: "127.0.0.3"},
+	})
+	assert.False(t, snapshot.Consistent)
+}
+
+func TestProcFS_ReadSnapshot(t *testing.T) {
+	root := t.TempDir()
+	writeProcFiles(t, root, map[string]string{
+		"net/sctp/assocs": ` ASSOC     SOCK   STY SST ST HBKT ASSOC-ID TX_QUEUE RX_QUEUE UID INODE LPORT RPORT LADDRS <-> RADDRS HBINT INS OUTS MAXRT T1X T2X RTXC wmema wmemq sndbuf rcvbuf
+       0        0 2   1   3  0      60        0      496       0 188897 12345 54321  127.0.0.1 <-> *127.0.0.2 	   30000 65535 65535   10    0    0        0        1        0   212992   212992
+`,
+		"net/sctp/eps": ` ENDPT     SOCK   STY SST HBKT LPORT   UID INODE LADDRS
+       0        0 2   10  24   12345     0 188897 127.0.0.1 
+`,
+		"net/sctp/remaddr": `ADDR ASSOC_ID HB_ACT RTO MAX_PATH_RTX REM_ADDR_RTX START STATE
+127.0.0.2  60 1 1000 5 0 0 2
+`,
+	})
+
+	before := time.Now()
+	snapshot, err := NewProcFS(root).ReadSnapshot(3)
+	assert.NoError(t, err)
+	assert.True(t, snapshot.Consistent)
+	assert.Equal(t, 1, snapshot.Attempts)
+	assert.False(t, snapshot.Time.Before(before))
+	assert.Len(t, snapshot.EPS, 1)
+	view, ok := snapshot.Lookup(60)
+	assert.True(t, ok)
+	assert.Equal(t, "127.0.0.2", view.PrimaryPath().Addr)
+}
+
+func TestProcFS_ReadSnapshot_Inconsistent(t *testing.T) {
+	root := t.TempDir()
+	writeProcFiles(t, root, map[string]string{
+		"net/sctp/assocs": ` ASSOC     SOCK   STY SST ST HBKT ASSOC-ID TX_QUEUE RX_QUEUE UID INODE LPORT RPORT LADDRS <-> RADDRS HBINT INS OUTS MAXRT T1X T2X RTXC wmema wmemq sndbuf rcvbuf
+`,
+		"net/sctp/eps": ` ENDPT     SOCK   STY SST HBKT LPORT   UID INODE LADDRS
+`,
+		"net/sctp/remaddr": `ADDR ASSOC_ID HB_ACT RTO MAX_PATH_RTX REM_ADDR_RTX START STATE
+127.0.0.2  60 1 1000 5 0 0 2
+`,
+	})
+
+	snapshot, err := NewProcFS(root).ReadSnapshot(3)
+	assert.NoError(t, err)
+	assert.False(t, snapshot.Consistent)
+	assert.Equal(t, 3, snapshot.Attempts)
+	assert.Len(t, snapshot.Orphans, 1)
+
+	snapshot, err = NewProcFS(root).ReadSnapshot(0)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, snapshot.Attempts)
+}
+
+func TestProcFS_ReadSnapshot_WithoutSCTP(t *testing.T) {
+	_, err := NewProcFS(t.TempDir()).ReadSnapshot(3)
+	assert.ErrorIs(t, err, ErrSCTPNotAvailable)
+}
+
+func TestProcFS_ReadSnapshot_ClientSocket(t *testing.T) {
+	root := t.TempDir()
+	writeProcFiles(t, root, map[string]string{
+		// the association has been initiated by connect() without listen(), so eps doesn't list its socket
+		"net/sctp/assocs": ` ASSOC     SOCK   STY SST ST HBKT ASSOC-ID TX_QUEUE RX_QUEUE UID INODE LPORT RPORT LADDRS <-> RADDRS HBINT INS OUTS MAXRT T1X T2X RTXC wmema wmemq sndbuf rcvbuf
+       0        0 2   1   3  0      60        0        0       0 188897 40000 3868  127.0.0.1 <-> *127.0.0.2 	   30000 10 10   10    0    0        0        1        0   212992   212992
+`,
+		"net/sctp/eps": ` ENDPT     SOCK   STY SST HBKT LPORT   UID INODE LADDRS
+       0        0 2   10  24   2905     0 100000 127.0.0.1
+`,
+		"net/sctp/remaddr": `ADDR ASSOC_ID HB_ACT RTO MAX_PATH_RTX REM_ADDR_RTX START STATE
+127.0.0.2  60 1 1000 5 0 0 2
+`,
+	})
+
+	snapshot, err := NewProcFS(root).ReadSnapshot(3)
+	assert.NoError(t, err)
+	assert.True(t, snapshot.Consistent)
+	assert.Equal(t, 1, snapshot.Attempts)
+	assert.Len(t, snapshot.EPS, 1)
+}
+
+func TestSnapshotReader_Clock(t *testing.T) {
+	clock := newFakeClock(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))
+	reader := NewSnapshotReader(NewMemorySource(nil, nil, nil, nil))
+	reader.Clock = clock
+
+	snapshot, err := reader.Read(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, clock.Now(), snapshot.Time)
+	assert.Equal(t, 1, snapshot.Attempts)
+}
+
+func TestSnapshotReader_Backoff(t *testing.T) {
+	clock := newFakeClock(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))
+	reader := NewSnapshotReader(NewMemorySource(nil, nil, []*Remaddr{{AssocID: 1, Addr: "10.0.0.2"}}, nil))
+	reader.Clock = clock
+
+	type result struct {
+		snapshot *Snapshot
+		err      error
+	}
+	results := make(chan result, 1)
+	go func() {
+		snapshot, err := reader.Read(context.Background())
+		results <- result{snapshot, err}
+	}()
+
+	// each retry waits for a tick of the backoff
+	clock.tick(DefaultSnapshotBackoff)
+	clock.tick(2 * DefaultSnapshotBackoff)
+
+	r := <-results
+	assert.NoError(t, r.err)
+	assert.False(t, r.snapshot.Consistent)
+	assert.Equal(t, 3, r.snapshot.Attempts)
+	assert.Equal(t, time.Date(2022, 1, 1, 0, 0, 0, int(3*DefaultSnapshotBackoff), time.UTC), r.snapshot.Time)
+}
+
+func TestSnapshotReader_CanceledWhileBackoff(t *testing.T) {
+	reader := NewSnapshotReader(NewMemorySource(nil, nil, []*Remaddr{{AssocID: 1, Addr: "10.0.0.2"}}, nil))
+	reader.Clock = newFakeClock(time.Now()) // never ticks
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := reader.Read(ctx)
+		errCh <- err
+	}()
+
+	cancel()
+	assert.ErrorIs(t, <-errCh, context.Canceled)
+}
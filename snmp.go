@@ -0,0 +1,152 @@
+package parser
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidSNMPFormat = errors.New("invalid SNMP format")
+)
+
+// SNMP represents the structure of SCTP SNMP; the MIB counters of SCTP.
+type SNMP struct {
+	CurrEstab               uint64
+	ActiveEstabs            uint64
+	PassiveEstabs           uint64
+	Aborteds                uint64
+	Shutdowns               uint64
+	OutOfBlues              uint64
+	ChecksumErrors          uint64
+	OutCtrlChunks           uint64
+	OutOrderChunks          uint64
+	OutUnorderChunks        uint64
+	InCtrlChunks            uint64
+	InOrderChunks           uint64
+	InUnorderChunks         uint64
+	FragUsrMsgs             uint64
+	ReasmUsrMsgs            uint64
+	OutSCTPPacks            uint64
+	InSCTPPacks             uint64
+	T1InitExpireds          uint64
+	T1CookieExpireds        uint64
+	T2ShutdownExpireds      uint64
+	T3RtxExpireds           uint64
+	T4RtoExpireds           uint64
+	T5ShutdownGuardExpireds uint64
+	DelaySackExpireds       uint64
+	AutocloseExpireds       uint64
+	T3Retransmits           uint64
+	PmtudRetransmits        uint64
+	FastRetransmits         uint64
+	InPktSoftirq            uint64
+	InPktBacklog            uint64
+	InPktDiscards           uint64
+	InDataChunkDiscards     uint64
+
+	// Unknown holds the counters that this package doesn't know, keyed by the names as they are (e.g. `SctpNewCounter`).
+	Unknown map[string]uint64
+}
+
+// snmpCounters maps the names of the SCTP MIB counters to the fields of SNMP.
+var snmpCounters = map[string]func(s *SNMP) *uint64{
+	"SctpCurrEstab":               func(s *SNMP) *uint64 { return &s.CurrEstab },
+	"SctpActiveEstabs":            func(s *SNMP) *uint64 { return &s.ActiveEstabs },
+	"SctpPassiveEstabs":           func(s *SNMP) *uint64 { return &s.PassiveEstabs },
+	"SctpAborteds":                func(s *SNMP) *uint64 { return &s.Aborteds },
+	"SctpShutdowns":               func(s *SNMP) *uint64 { return &s.Shutdowns },
+	"SctpOutOfBlues":              func(s *SNMP) *uint64 { return &s.OutOfBlues },
+	"SctpChecksumErrors":          func(s *SNMP) *uint64 { return &s.ChecksumErrors },
+	"SctpOutCtrlChunks":           func(s *SNMP) *uint64 { return &s.OutCtrlChunks },
+	"SctpOutOrderChunks":          func(s *SNMP) *uint64 { return &s.OutOrderChunks },
+	"SctpOutUnorderChunks":        func(s *SNMP) *uint64 { return &s.OutUnorderChunks },
+	"SctpInCtrlChunks":            func(s *SNMP) *uint64 { return &s.InCtrlChunks },
+	"SctpInOrderChunks":           func(s *SNMP) *uint64 { return &s.InOrderChunks },
+	"SctpInUnorderChunks":         func(s *SNMP) *uint64 { return &s.InUnorderChunks },
+	"SctpFragUsrMsgs":             func(s *SNMP) *uint64 { return &s.FragUsrMsgs },
+	"SctpReasmUsrMsgs":            func(s *SNMP) *uint64 { return &s.ReasmUsrMsgs },
+	"SctpOutSCTPPacks":            func(s *SNMP) *uint64 { return &s.OutSCTPPacks },
+	"SctpInSCTPPacks":             func(s *SNMP) *uint64 { return &s.InSCTPPacks },
+	"SctpT1InitExpireds":          func(s *SNMP) *uint64 { return &s.T1InitExpireds },
+	"SctpT1CookieExpireds":        func(s *SNMP) *uint64 { return &s.T1CookieExpireds },
+	"SctpT2ShutdownExpireds":      func(s *SNMP) *uint64 { return &s.T2ShutdownExpireds },
+	"SctpT3RtxExpireds":           func(s *SNMP) *uint64 { return &s.T3RtxExpireds },
+	"SctpT4RtoExpireds":           func(s *SNMP) *uint64 { return &s.T4RtoExpireds },
+	"SctpT5ShutdownGuardExpireds": func(s *SNMP) *uint64 { return &s.T5ShutdownGuardExpireds },
+	"SctpDelaySackExpireds":       func(s *SNMP) *uint64 { return &s.DelaySackExpireds },
+	"SctpAutocloseExpireds":       func(s *SNMP) *uint64 { return &s.AutocloseExpireds },
+	"SctpT3Retransmits":           func(s *SNMP) *uint64 { return &s.T3Retransmits },
+	"SctpPmtudRetransmits":        func(s *SNMP) *uint64 { return &s.PmtudRetransmits },
+	"SctpFastRetransmits":         func(s *SNMP) *uint64 { return &s.FastRetransmits },
+	"SctpInPktSoftirq":            func(s *SNMP) *uint64 { return &s.InPktSoftirq },
+	"SctpInPktBacklog":            func(s *SNMP) *uint64 { return &s.InPktBacklog },
+	"SctpInPktDiscards":           func(s *SNMP) *uint64 { return &s.InPktDiscards },
+	"SctpInDataChunkDiscards":     func(s *SNMP) *uint64 { return &s.InDataChunkDiscards },
+}
+
+// Counters returns all the counters keyed by the names as they are in the file (e.g. `SctpCurrEstab`), including Unknown ones.
+func (s *SNMP) Counters() map[string]uint64 {
+	counters := make(map[string]uint64, len(snmpCounters)+len(s.Unknown))
+	for name, counter := range snmpCounters {
+		counters[name] = *counter(s)
+	}
+	for name, value := range s.Unknown {
+		counters[name] = value
+	}
+	return counters
+}
+
+// ParseSNMP parses SCTP SNMP contents; for example the contents of `/proc/net/sctp/snmp` file.
+//
+// - input: the contents of SCTP SNMP
+//
+// example input:
+// ```
+// SctpCurrEstab                   	2
+// SctpActiveEstabs                	1
+// SctpPassiveEstabs               	1
+// SctpAborteds                    	0
+// ```
+func ParseSNMP(input *bufio.Scanner) (*SNMP, error) {
+	// implementation memo:
+	// https://github.com/torvalds/linux/blob/dcc0b49040c70ad827a7f3d58a21b01fdb14e749/net/sctp/proc.c
+
+	snmp := &SNMP{
+		Unknown: make(map[string]uint64),
+	}
+
+	lineNum := 1
+	for input.Scan() {
+		if strings.TrimSpace(input.Text()) == "" {
+			lineNum++
+			continue
+		}
+
+		leaves := spacesRe.Split(strings.TrimSpace(input.Text()), -1)
+		if len(leaves) != 2 {
+			return nil, fmt.Errorf("at line #%d: %w", lineNum, ErrInvalidSNMPFormat)
+		}
+
+		name := leaves[0]
+		value, err := strconv.ParseUint(leaves[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s at line #%d: %w", name, lineNum, ErrInvalidSNMPFormat)
+		}
+
+		if counter, ok := snmpCounters[name]; ok {
+			*counter(snmp) = value
+		} else {
+			snmp.Unknown[name] = value
+		}
+
+		lineNum++
+	}
+	if err := input.Err(); err != nil {
+		return nil, err
+	}
+
+	return snmp, nil
+}
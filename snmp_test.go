@@ -0,0 +1,134 @@
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSNMP(t *testing.T) {
+	input := `SctpCurrEstab                   	2
+SctpActiveEstabs                	1
+SctpPassiveEstabs               	1
+SctpAborteds                    	3
+SctpShutdowns                   	4
+SctpOutOfBlues                  	5
+SctpChecksumErrors              	0
+SctpOutCtrlChunks               	120
+SctpOutOrderChunks              	10
+SctpOutUnorderChunks            	0
+SctpInCtrlChunks                	118
+SctpInOrderChunks               	9
+SctpInUnorderChunks             	0
+SctpFragUsrMsgs                 	0
+SctpReasmUsrMsgs                	0
+SctpOutSCTPPacks                	130
+SctpInSCTPPacks                 	127
+SctpT1InitExpireds              	6
+SctpT1CookieExpireds            	0
+SctpT2ShutdownExpireds          	0
+SctpT3RtxExpireds               	7
+SctpT4RtoExpireds               	0
+SctpT5ShutdownGuardExpireds     	0
+SctpDelaySackExpireds           	8
+SctpAutocloseExpireds           	0
+SctpT3Retransmits               	9
+SctpPmtudRetransmits            	0
+SctpFastRetransmits             	0
+SctpInPktSoftirq                	127
+SctpInPktBacklog                	0
+SctpInPktDiscards               	0
+SctpInDataChunkDiscards         	0
+SctpNewCounter                  	42
+`
+	snmp, err := ParseSNMP(bufio.NewScanner(strings.NewReader(input)))
+	assert.NoError(t, err)
+	assert.EqualValues(t, &SNMP{
+		CurrEstab:               2,
+		ActiveEstabs:            1,
+		PassiveEstabs:           1,
+		Aborteds:                3,
+		Shutdowns:               4,
+		OutOfBlues:              5,
+		ChecksumErrors:          0,
+		OutCtrlChunks:           120,
+		OutOrderChunks:          10,
+		OutUnorderChunks:        0,
+		InCtrlChunks:            118,
+		InOrderChunks:           9,
+		InUnorderChunks:         0,
+		FragUsrMsgs:             0,
+		ReasmUsrMsgs:            0,
+		OutSCTPPacks:            130,
+		InSCTPPacks:             127,
+		T1InitExpireds:          6,
+		T1CookieExpireds:        0,
+		T2ShutdownExpireds:      0,
+		T3RtxExpireds:           7,
+		T4RtoExpireds:           0,
+		T5ShutdownGuardExpireds: 0,
+		DelaySackExpireds:       8,
+		AutocloseExpireds:       0,
+		T3Retransmits:           9,
+		PmtudRetransmits:        0,
+		FastRetransmits:         0,
+		InPktSoftirq:            127,
+		InPktBacklog:            0,
+		InPktDiscards:           0,
+		InDataChunkDiscards:     0,
+		Unknown: map[string]uint64{
+			"SctpNewCounter": 42,
+		},
+	}, snmp)
+}
+
+func TestParseSNMP_WithInvalidInput(t *testing.T) {
+	input := `SctpCurrEstab                   	2
+SctpActiveEstabs                	x
+`
+	_, err := ParseSNMP(bufio.NewScanner(strings.NewReader(input)))
+	assert.ErrorIs(t, err, ErrInvalidSNMPFormat)
+	assert.Contains(t, err.Error(), "SctpActiveEstabs at line #2")
+}
+
+func TestParseSNMP_WithInsufficientItems(t *testing.T) {
+	input := `SctpCurrEstab                   	2
+
+SctpActiveEstabs
+`
+	_, err := ParseSNMP(bufio.NewScanner(strings.NewReader(input)))
+	assert.ErrorIs(t, err, ErrInvalidSNMPFormat)
+	assert.Contains(t, err.Error(), "at line #3")
+}
+
+func TestSNMP_Counters(t *testing.T) {
+	snmp := &SNMP{
+		CurrEstab:     2,
+		T3Retransmits: 5,
+		Unknown:       map[string]uint64{"SctpNewCounter": 42},
+	}
+
+	counters := snmp.Counters()
+	assert.Len(t, counters, len(snmpCounters)+1)
+	assert.EqualValues(t, 2, counters["SctpCurrEstab"])
+	assert.EqualValues(t, 5, counters["SctpT3Retransmits"])
+	assert.EqualValues(t, 0, counters["SctpAborteds"])
+	assert.EqualValues(t, 42, counters["SctpNewCounter"])
+}
+
+func ExampleParseSNMP() {
+	input := `SctpCurrEstab                   	2
+SctpActiveEstabs                	1
+SctpPassiveEstabs               	1
+SctpAborteds                    	0
+`
+	snmp, err := ParseSNMP(bufio.NewScanner(strings.NewReader(input)))
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%v\n", snmp)
+}
@@ -0,0 +1,66 @@
+//go:build linux
+
+package sockdiag
+
+import (
+	"os"
+	"syscall"
+)
+
+// Dump dumps all the SCTP sockets and associations of the current network namespace via sock_diag.
+// Both of the IPv4 and IPv6 sockets are dumped.
+//
+// hz is the kernel's CONFIG_HZ; see DecodeDump.
+//
+// It returns parser.ErrSCTPNotAvailable if the kernel doesn't support sock_diag of SCTP; e.g. the sctp_diag module is not loaded.
+func Dump(hz int) (*Result, error) {
+	fd, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_RAW|syscall.SOCK_CLOEXEC, netlinkSockDiag)
+	if err != nil {
+		return nil, os.NewSyscallError("socket", err)
+	}
+	defer syscall.Close(fd)
+
+	if err := syscall.Bind(fd, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK}); err != nil {
+		return nil, os.NewSyscallError("bind", err)
+	}
+
+	payloads := make([][]byte, 0)
+	for i, family := range []uint8{afInet, afInet6} {
+		p, err := dumpFamily(fd, family, uint32(i+1))
+		if err != nil {
+			return nil, err
+		}
+		payloads = append(payloads, p...)
+	}
+
+	return DecodeDump(payloads, hz)
+}
+
+func dumpFamily(fd int, family uint8, seq uint32) ([][]byte, error) {
+	if err := syscall.Sendto(fd, encodeRequest(family, seq), 0, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK}); err != nil {
+		return nil, os.NewSyscallError("sendto", err)
+	}
+
+	payloads := make([][]byte, 0)
+	buf := make([]byte, os.Getpagesize()*8)
+	for {
+		n, _, err := syscall.Recvfrom(fd, buf, 0)
+		if err != nil {
+			if err == syscall.EINTR {
+				continue
+			}
+			return nil, os.NewSyscallError("recvfrom", err)
+		}
+
+		p, done, err := parseMessages(buf[:n])
+		if err != nil {
+			return nil, err
+		}
+		for _, payload := range p {
+			payloads = append(payloads, append([]byte(nil), payload...)) // buf is reused
+		}
+		if done {
+			return payloads, nil
+		}
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package sockdiag
+
+// Dump returns ErrNotSupported; sock_diag is only available on Linux.
+func Dump(hz int) (*Result, error) {
+	return nil, ErrNotSupported
+}
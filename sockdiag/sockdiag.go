@@ -0,0 +1,324 @@
+// Package sockdiag reads SCTP sockets via the sock_diag netlink interface (NETLINK_SOCK_DIAG), as `ss --sctp` does,
+// instead of the proc filesystem.
+//
+// The records are converted into the same types as the proc files (parser.Assoc, parser.EPS and parser.Remaddr),
+// and the information that only sock_diag provides (struct sctp_info) is available as Info.
+//
+// Note that sock_diag and the proc files don't carry the same information:
+//
+//   - sock_diag doesn't report the association IDs, the kernel addresses (ASSOC, SOCK and ENDPT), HBKT, HBINT, MAXRT, T1X, T2X, RTXC and wmema.
+//     They are marked in parser.Assoc.Absent.
+//   - In place of the association IDs, parser.Assoc.AssocId and parser.Remaddr.AssocID are the local verification tags (Info.Tag),
+//     which identify the associations as well, so that the paths can be joined with the associations (e.g. by parser.NewSnapshot).
+//     They are 0 if the kernel didn't attach struct sctp_info.
+//   - sock_diag dumps all the sockets, whereas eps lists only the listening ones. Result.EPS (and so Source.Endpoints) returns
+//     the listening ones to match eps, and Result.Endpoints has all of them.
+//   - sctp_info reports the RTO in milliseconds, whereas the proc files do in jiffies. parser.Remaddr.RTO is converted into jiffies
+//     with the kernel's CONFIG_HZ, so that it has the same unit regardless of the source; Info.Primary.RTO keeps the milliseconds.
+//   - sctp_info describes the primary path only; cwnd, srtt and rto of the other paths are not available, and there is no rttvar at all.
+//     So the Remaddr entries other than the primary one have only the addresses, with parser.TransportStateUnknown as the state.
+//
+// The wire format is encoded and decoded apart from the socket I/O, which is available only on Linux.
+package sockdiag
+
+import (
+	"fmt"
+	"time"
+
+	parser "github.com/moznion/go-sctp-proc-parser"
+)
+
+// Info is the information of an association in struct sctp_info.
+type Info struct {
+	Tag                uint32
+	State              parser.AssocState
+	Rwnd               uint32
+	UnackData          uint16
+	PendData           uint16
+	InStreams          uint16
+	OutStreams         uint16
+	FragmentationPoint uint32
+	InQueue            uint32
+	OutQueue           uint32
+	OverallError       uint32
+	MaxBurst           uint32
+	MaxSeg             uint32
+	PeerRwnd           uint32
+	PeerTag            uint32
+	PeerCapable        uint8
+	PeerSack           uint8
+
+	ISacks               uint64
+	OSacks               uint64
+	OPackets             uint64
+	IPackets             uint64
+	RtxChunks            uint64
+	OutOfSeqTSNs         uint64
+	IDupChunks           uint64
+	GapCount             uint64
+	OUnorderedDataChunks uint64
+	IUnorderedDataChunks uint64
+	OOrderedDataChunks   uint64
+	IOrderedDataChunks   uint64
+	OCtrlChunks          uint64
+	ICtrlChunks          uint64
+
+	// Primary is the information of the primary path.
+	Primary PrimaryPathInfo
+	// Socket is the information of the socket. The kernel fills this only for the socket records, i.e. Endpoint.Info.
+	Socket SocketInfo
+}
+
+// PrimaryPathInfo is the information of the primary path of an association.
+type PrimaryPathInfo struct {
+	Addr  string
+	State parser.TransportState
+	Cwnd  uint32
+	// SRTT is the smoothed round trip time in jiffies, as the kernel reports it.
+	SRTT uint32
+	// RTO is the retransmission timeout in milliseconds.
+	RTO uint32
+	// HBInterval is the heartbeat interval in jiffies, as the kernel reports it.
+	HBInterval uint32
+	PathMaxRxt uint32
+	// SackDelay is the delay of SACK in milliseconds.
+	SackDelay         uint32
+	SackFreq          uint32
+	Ssthresh          uint32
+	PartialBytesAcked uint32
+	FlightSize        uint32
+	Error             uint16
+}
+
+// SocketInfo is the information of an SCTP socket.
+type SocketInfo struct {
+	Autoclose        uint32
+	AdaptationInd    uint32
+	PDPoint          uint32
+	NoDelay          bool
+	DisableFragments bool
+	V4Mapped         bool
+	FragInterleave   uint8
+	Type             parser.SocketType
+}
+
+// Association is an association that has been dumped via sock_diag.
+type Association struct {
+	Assoc *parser.Assoc
+	// Paths are the paths to the remote addresses, in the order of Assoc.RAddrs. See the package document for the limitation.
+	Paths []*parser.Remaddr
+	// Info is nil if the kernel didn't attach struct sctp_info.
+	Info *Info
+	// Cookie is the socket cookie, which identifies the socket together with Assoc.Inode.
+	Cookie uint64
+}
+
+// Endpoint is an SCTP socket that has been dumped via sock_diag.
+type Endpoint struct {
+	EPS *parser.EPS
+	// Info is nil if the kernel didn't attach struct sctp_info. Only Info.Socket is filled for the endpoints.
+	Info   *Info
+	Cookie uint64
+}
+
+// Result is the result of a dump.
+type Result struct {
+	Associations []*Association
+	// Endpoints are all the sockets, including the ones that are not listening; e.g. the client sockets of one-to-one style.
+	Endpoints []*Endpoint
+}
+
+// Assocs returns the assocs of the associations.
+func (r *Result) Assocs() []*parser.Assoc {
+	assocs := make([]*parser.Assoc, len(r.Associations))
+	for i, association := range r.Associations {
+		assocs[i] = association.Assoc
+	}
+	return assocs
+}
+
+// EPS returns the EPS records of the listening endpoints, as `/proc/net/sctp/eps` lists only them.
+func (r *Result) EPS() []*parser.EPS {
+	epses := make([]*parser.EPS, 0, len(r.Endpoints))
+	for _, endpoint := range r.Endpoints {
+		if endpoint.EPS.Sst.IsListening() {
+			epses = append(epses, endpoint.EPS)
+		}
+	}
+	return epses
+}
+
+// Remaddrs returns the paths of all the associations.
+func (r *Result) Remaddrs() []*parser.Remaddr {
+	remaddrs := make([]*parser.Remaddr, 0, len(r.Associations))
+	for _, association := range r.Associations {
+		remaddrs = append(remaddrs, association.Paths...)
+	}
+	return remaddrs
+}
+
+// unavailableAssocFields are the columns of the proc files that sock_diag doesn't report.
+const unavailableAssocFields = parser.AssocFieldAssoc | parser.AssocFieldSock | parser.AssocFieldHbkt | parser.AssocFieldAssocId |
+	parser.AssocFieldHbint | parser.AssocFieldMaxrt | parser.AssocFieldT1x | parser.AssocFieldT2x | parser.AssocFieldRtxc |
+	parser.AssocFieldWmema
+
+// DecodeDump decodes the payloads of the SOCK_DIAG_BY_FAMILY messages of SCTP dumps; i.e. struct inet_diag_msg with the attributes.
+//
+// The kernel emits a message of the socket before the messages of its associations. The messages of the sockets
+// become the endpoints (deduplicated by the inode), and the socket type and state of the associations are taken from them.
+// hz is the kernel's CONFIG_HZ, with which the RTO of the paths is converted into jiffies; less than 1 is treated as parser.DefaultHZ.
+func DecodeDump(payloads [][]byte, hz int) (*Result, error) {
+	msgs := make([]*diagMsg, len(payloads))
+	for i, payload := range payloads {
+		msg, err := decodeDiagMsg(payload)
+		if err != nil {
+			return nil, fmt.Errorf("message #%d: %w", i+1, err)
+		}
+		msgs[i] = msg
+	}
+
+	result := &Result{
+		Associations: make([]*Association, 0),
+		Endpoints:    make([]*Endpoint, 0),
+	}
+	endpoints := make(map[uint32]*Endpoint)
+	for i, msg := range msgs {
+		if msg.hasPeer {
+			continue
+		}
+		if _, ok := endpoints[msg.inode]; ok {
+			continue
+		}
+		endpoint, err := decodeEndpoint(msg)
+		if err != nil {
+			return nil, fmt.Errorf("message #%d: %w", i+1, err)
+		}
+		endpoints[msg.inode] = endpoint
+		result.Endpoints = append(result.Endpoints, endpoint)
+	}
+
+	for i, msg := range msgs {
+		if !msg.hasPeer {
+			continue
+		}
+		association, err := decodeAssociation(msg, endpoints[msg.inode], hz)
+		if err != nil {
+			return nil, fmt.Errorf("message #%d: %w", i+1, err)
+		}
+		result.Associations = append(result.Associations, association)
+	}
+
+	return result, nil
+}
+
+func decodeEndpoint(msg *diagMsg) (*Endpoint, error) {
+	laddrs, laddrIPs, err := decodeSockaddrs(msg.attrs[inetDiagLocals])
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := &Endpoint{
+		EPS: &parser.EPS{
+			Sst:      parser.SocketState(msg.state),
+			LPort:    int64(msg.sport),
+			Uid:      uint64(msg.uid),
+			Inode:    uint64(msg.inode),
+			LAddrs:   laddrs,
+			LAddrIPs: laddrIPs,
+		},
+		Cookie: msg.cookie,
+	}
+	if b, ok := msg.attrs[inetDiagInfo]; ok {
+		info, err := decodeSCTPInfo(b)
+		if err != nil {
+			return nil, err
+		}
+		endpoint.Info = info
+		endpoint.EPS.Sty = info.Socket.Type
+	}
+
+	return endpoint, nil
+}
+
+func decodeAssociation(msg *diagMsg, endpoint *Endpoint, hz int) (*Association, error) {
+	laddrs, laddrIPs, err := decodeSockaddrs(msg.attrs[inetDiagLocals])
+	if err != nil {
+		return nil, err
+	}
+	raddrs, raddrIPs, err := decodeSockaddrs(msg.attrs[inetDiagPeers])
+	if err != nil {
+		return nil, err
+	}
+
+	assoc := &parser.Assoc{
+		St:       parser.AssocState(msg.state),
+		TxQueue:  int64(msg.wqueue),
+		RxQueue:  int64(msg.rqueue),
+		Uid:      uint64(msg.uid),
+		Inode:    uint64(msg.inode),
+		LPort:    int64(msg.sport),
+		RPort:    int64(msg.dport),
+		LAddrs:   laddrs,
+		RAddrs:   raddrs,
+		LAddrIPs: laddrIPs,
+		RAddrIPs: raddrIPs,
+		Absent:   unavailableAssocFields,
+	}
+
+	if endpoint != nil {
+		assoc.Sty = endpoint.EPS.Sty
+		assoc.Sst = endpoint.EPS.Sst
+		if endpoint.Info == nil {
+			assoc.Absent |= parser.AssocFieldSty
+		}
+	} else {
+		assoc.Absent |= parser.AssocFieldSty | parser.AssocFieldSst
+	}
+
+	if b, ok := msg.attrs[inetDiagSKMemInfo]; ok {
+		mem, err := decodeMemInfo(b)
+		if err != nil {
+			return nil, err
+		}
+		assoc.Wmemq = int64(mem[skMemInfoWmemQueued])
+		assoc.Sndbuf = int64(mem[skMemInfoSndbuf])
+		assoc.Rcvbuf = int64(mem[skMemInfoRcvbuf])
+	} else {
+		assoc.Absent |= parser.AssocFieldWmemq | parser.AssocFieldSndbuf | parser.AssocFieldRcvbuf
+	}
+
+	association := &Association{Assoc: assoc, Cookie: msg.cookie}
+	if b, ok := msg.attrs[inetDiagInfo]; ok {
+		info, err := decodeSCTPInfo(b)
+		if err != nil {
+			return nil, err
+		}
+		association.Info = info
+		assoc.AssocId = int64(info.Tag)
+		assoc.Ins = int64(info.InStreams)
+		assoc.Outs = int64(info.OutStreams)
+		assoc.PrimaryRAddr = info.Primary.Addr
+	} else {
+		assoc.Absent |= parser.AssocFieldIns | parser.AssocFieldOuts
+	}
+
+	association.Paths = make([]*parser.Remaddr, len(raddrs))
+	for i, raddr := range raddrs {
+		path := &parser.Remaddr{
+			Addr:    raddr,
+			AssocID: assoc.AssocId,
+			State:   parser.TransportStateUnknown,
+			AddrIP:  raddrIPs[i],
+		}
+		if association.Info != nil && raddr == association.Info.Primary.Addr {
+			primary := association.Info.Primary
+			path.State = primary.State
+			path.RTO = parser.DurationToJiffies(time.Duration(primary.RTO)*time.Millisecond, hz)
+			path.MaxPathRtx = int64(primary.PathMaxRxt)
+			path.RemAddrRtx = int64(primary.Error)
+		}
+		association.Paths[i] = path
+	}
+
+	return association, nil
+}
@@ -0,0 +1,168 @@
+package sockdiag
+
+import (
+	"net/netip"
+	"testing"
+
+	parser "github.com/moznion/go-sctp-proc-parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func decodeFixture(t *testing.T, names ...string) *Result {
+	t.Helper()
+	return decodeFixtureWithHZ(t, parser.DefaultHZ, names...)
+}
+
+func decodeFixtureWithHZ(t *testing.T, hz int, names ...string) *Result {
+	t.Helper()
+	payloads := make([][]byte, 0)
+	for _, name := range names {
+		for _, datagram := range readDatagrams(t, name) {
+			p, _, err := parseMessages(datagram)
+			assert.NoError(t, err)
+			payloads = append(payloads, p...)
+		}
+	}
+	result, err := DecodeDump(payloads, hz)
+	assert.NoError(t, err)
+	return result
+}
+
+func TestDecodeDump(t *testing.T) {
+	result := decodeFixture(t, "dump_inet.hex", "dump_inet6.hex")
+
+	assert.Len(t, result.Endpoints, 2) // the duplicated socket message is skipped
+	assert.Equal(t, &parser.EPS{
+		Sty:      parser.SocketTypeOneToOne,
+		Sst:      parser.SocketStateListen,
+		LPort:    3868,
+		Inode:    1000,
+		LAddrs:   []string{"10.0.0.1", "10.0.0.5"},
+		LAddrIPs: []netip.Addr{netip.MustParseAddr("10.0.0.1"), netip.MustParseAddr("10.0.0.5")},
+	}, result.Endpoints[0].EPS)
+	assert.EqualValues(t, 0x1122334455667788, result.Endpoints[0].Cookie)
+	assert.True(t, result.Endpoints[0].Info.Socket.NoDelay)
+	assert.Equal(t, parser.SocketTypeOneToMany, result.Endpoints[1].EPS.Sty)
+	assert.EqualValues(t, 2905, result.Endpoints[1].EPS.LPort)
+	assert.EqualValues(t, 1000, result.Endpoints[1].EPS.Uid)
+
+	assert.Len(t, result.Associations, 2)
+
+	association := result.Associations[0]
+	assert.Equal(t, &parser.Assoc{
+		Sty:          parser.SocketTypeOneToMany,
+		Sst:          parser.SocketStateListen,
+		St:           parser.AssocStateEstablished,
+		TxQueue:      100,
+		RxQueue:      496,
+		Uid:          1000,
+		Inode:        2000,
+		LPort:        2905,
+		RPort:        50000,
+		LAddrs:       []string{"10.0.0.1"},
+		RAddrs:       []string{"10.0.0.2", "10.0.0.3"},
+		LAddrIPs:     []netip.Addr{netip.MustParseAddr("10.0.0.1")},
+		RAddrIPs:     []netip.Addr{netip.MustParseAddr("10.0.0.2"), netip.MustParseAddr("10.0.0.3")},
+		Ins:          10,
+		Outs:         12,
+		Wmemq:        1280,
+		Sndbuf:       212992,
+		Rcvbuf:       212992,
+		PrimaryRAddr: "10.0.0.3",
+		AssocId:      0x01020304,
+		Absent:       unavailableAssocFields,
+	}, association.Assoc)
+	assert.False(t, association.Assoc.Has(parser.AssocFieldAssocId))
+	assert.True(t, association.Assoc.Has(parser.AssocFieldWmemq))
+	assert.EqualValues(t, 0x99, association.Cookie)
+
+	assert.Equal(t, []*parser.Remaddr{
+		{Addr: "10.0.0.2", AssocID: 0x01020304, State: parser.TransportStateUnknown, AddrIP: netip.MustParseAddr("10.0.0.2")},
+		{Addr: "10.0.0.3", AssocID: 0x01020304, State: parser.TransportStateActive, RTO: 3000, MaxPathRtx: 5, RemAddrRtx: 1, AddrIP: netip.MustParseAddr("10.0.0.3")},
+	}, association.Paths)
+
+	info := association.Info
+	assert.EqualValues(t, 0x01020304, info.Tag)
+	assert.Equal(t, parser.AssocStateEstablished, info.State)
+	assert.EqualValues(t, 100, info.OPackets)
+	assert.EqualValues(t, 90, info.IPackets)
+	assert.EqualValues(t, 7, info.RtxChunks)
+	assert.Equal(t, PrimaryPathInfo{
+		Addr:       "10.0.0.3",
+		State:      parser.TransportStateActive,
+		Cwnd:       4380,
+		SRTT:       50,
+		RTO:        3000,
+		HBInterval: 30000,
+		PathMaxRxt: 5,
+		SackDelay:  200,
+		SackFreq:   2,
+		Ssthresh:   0x7fffffff,
+		Error:      1,
+	}, info.Primary)
+
+	// the association of the one-to-one socket doesn't have the socket message, nor the memory information
+	association = result.Associations[1]
+	assert.Equal(t, parser.AssocStateShutdownPending, association.Assoc.St)
+	assert.Equal(t, []string{"2001:0db8:0000:0000:0000:0000:0000:0001"}, association.Assoc.LAddrs)
+	assert.Equal(t, []string{"2001:0db8:0000:0000:0000:0000:0000:0002"}, association.Assoc.RAddrs)
+	assert.Equal(t, "2001:0db8:0000:0000:0000:0000:0000:0002", association.Assoc.PrimaryRAddr)
+	assert.EqualValues(t, 3868, association.Assoc.LPort)
+	assert.EqualValues(t, 40000, association.Assoc.RPort)
+	assert.EqualValues(t, 1, association.Assoc.Ins)
+	for _, field := range []parser.AssocField{parser.AssocFieldSty, parser.AssocFieldSst, parser.AssocFieldWmemq, parser.AssocFieldSndbuf, parser.AssocFieldRcvbuf} {
+		assert.False(t, association.Assoc.Has(field))
+	}
+	assert.True(t, association.Assoc.Has(parser.AssocFieldIns))
+	assert.Equal(t, parser.TransportStateInactive, association.Paths[0].State)
+}
+
+func TestResult(t *testing.T) {
+	result := decodeFixture(t, "dump_inet.hex", "dump_inet6.hex")
+
+	assocs := result.Assocs()
+	assert.Len(t, assocs, 2)
+	assert.Same(t, result.Associations[1].Assoc, assocs[1])
+
+	eps := result.EPS()
+	assert.Len(t, eps, 2)
+	assert.Same(t, result.Endpoints[0].EPS, eps[0])
+
+	remaddrs := result.Remaddrs()
+	assert.Len(t, remaddrs, 3)
+	assert.Equal(t, "10.0.0.2", remaddrs[0].Addr)
+	assert.Equal(t, "2001:0db8:0000:0000:0000:0000:0000:0002", remaddrs[2].Addr)
+
+	// the records can be written in the proc text format
+	assert.Contains(t, parser.FormatEPS(eps), "3868")
+
+	// and can be joined by the verification tags
+	snapshot := parser.NewSnapshot(assocs, remaddrs)
+	assert.True(t, snapshot.Consistent)
+	view, ok := snapshot.Lookup(0x0a0b0c0d)
+	assert.True(t, ok)
+	assert.Equal(t, remaddrs[2], view.PrimaryPath())
+}
+
+func TestResult_EPS(t *testing.T) {
+	result := &Result{Endpoints: []*Endpoint{
+		{EPS: &parser.EPS{Sst: parser.SocketStateListen, LPort: 3868, Inode: 1}},
+		{EPS: &parser.EPS{Sst: parser.SocketStateEstablished, LPort: 40000, Inode: 2}}, // a client socket of one-to-one style
+	}}
+
+	assert.Equal(t, []*parser.EPS{result.Endpoints[0].EPS}, result.EPS())
+}
+
+func TestDecodeDump_HZ(t *testing.T) {
+	result := decodeFixtureWithHZ(t, 250, "dump_inet.hex", "dump_inet6.hex")
+
+	association := result.Associations[0]
+	assert.EqualValues(t, 750, association.Paths[1].RTO) // 3000ms in jiffies at HZ=250
+	assert.EqualValues(t, 3000, association.Info.Primary.RTO)
+}
+
+func TestDecodeDump_Invalid(t *testing.T) {
+	_, err := DecodeDump([][]byte{make([]byte, 10)}, parser.DefaultHZ)
+	assert.ErrorIs(t, err, ErrInvalidMessage)
+	assert.EqualError(t, err, "message #1: inet_diag_msg: invalid sock_diag message")
+}
@@ -0,0 +1,66 @@
+package sockdiag
+
+import (
+	"context"
+
+	parser "github.com/moznion/go-sctp-proc-parser"
+)
+
+// Source is a parser.Source that dumps the SCTP sockets via sock_diag on every call.
+//
+// sock_diag doesn't carry the SCTP MIB counters, so SNMP reads them from the proc filesystem.
+// Each call makes a dump of its own; the associations and the paths of different dumps are joined by the verification tags
+// (see the package document), and parser.ReadSnapshotFrom reads them again if they disagree.
+type Source struct {
+	// HZ is the kernel's CONFIG_HZ, with which the RTO of the paths is converted into jiffies (see DecodeDump).
+	// This is parser.DefaultHZ by default.
+	HZ int
+
+	procFS parser.ProcFS
+	dump   func(hz int) (*Result, error)
+}
+
+// NewSource returns a new Source that reads the SNMP counters from the proc filesystem.
+func NewSource(procFS parser.ProcFS) Source {
+	return Source{HZ: parser.DefaultHZ, procFS: procFS, dump: Dump}
+}
+
+// Assocs implements parser.Source.
+func (s Source) Assocs(ctx context.Context) ([]*parser.Assoc, error) {
+	result, err := s.dumpContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return result.Assocs(), nil
+}
+
+// Endpoints implements parser.Source. The endpoints are the listening sockets only, as the proc filesystem has; see Result.EPS.
+func (s Source) Endpoints(ctx context.Context) ([]*parser.EPS, error) {
+	result, err := s.dumpContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return result.EPS(), nil
+}
+
+// Paths implements parser.Source. See the package document for the limitation of the paths.
+func (s Source) Paths(ctx context.Context) ([]*parser.Remaddr, error) {
+	result, err := s.dumpContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return result.Remaddrs(), nil
+}
+
+// SNMP implements parser.Source; it reads `net/sctp/snmp` file of the proc filesystem.
+func (s Source) SNMP(ctx context.Context) (*parser.SNMP, error) {
+	return s.procFS.SNMP(ctx)
+}
+
+// dumpContext dumps the sockets unless the context is done. The dump itself can't be canceled.
+func (s Source) dumpContext(ctx context.Context) (*Result, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return s.dump(s.HZ)
+}
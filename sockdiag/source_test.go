@@ -0,0 +1,61 @@
+package sockdiag
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	parser "github.com/moznion/go-sctp-proc-parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSource(t *testing.T) {
+	result := decodeFixture(t, "dump_inet.hex", "dump_inet6.hex")
+
+	root := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "net", "sctp"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "net", "sctp", "snmp"), []byte("SctpCurrEstab                   \t2\n"), 0o644))
+
+	source := NewSource(parser.NewProcFS(root))
+	source.HZ = 250
+	dumps := 0
+	source.dump = func(hz int) (*Result, error) {
+		assert.Equal(t, 250, hz)
+		dumps++
+		return result, nil
+	}
+
+	var _ parser.Source = source
+	ctx := context.Background()
+
+	snapshot, err := parser.ReadSnapshotFrom(ctx, source, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, dumps)
+	assert.True(t, snapshot.Consistent)
+	assert.Len(t, snapshot.Associations, 2)
+	assert.Len(t, snapshot.EPS, 2)
+	assert.Equal(t, "10.0.0.3", snapshot.Associations[0].PrimaryPath().Addr)
+
+	snmp, err := source.SNMP(ctx)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, snmp.CurrEstab)
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = source.Paths(canceled)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 3, dumps)
+}
+
+func TestSource_DumpError(t *testing.T) {
+	source := NewSource(parser.NewProcFS(t.TempDir()))
+	source.dump = func(hz int) (*Result, error) {
+		return nil, parser.ErrSCTPNotAvailable
+	}
+
+	_, err := source.Assocs(context.Background())
+	assert.ErrorIs(t, err, parser.ErrSCTPNotAvailable)
+	_, err = source.SNMP(context.Background())
+	assert.ErrorIs(t, err, parser.ErrSCTPNotAvailable)
+}
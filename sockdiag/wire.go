@@ -0,0 +1,318 @@
+package sockdiag
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/netip"
+	"unsafe"
+
+	parser "github.com/moznion/go-sctp-proc-parser"
+)
+
+var (
+	ErrInvalidMessage = errors.New("invalid sock_diag message")
+	ErrDumpFailed     = errors.New("sock_diag dump failed")
+	ErrNotSupported   = errors.New("sock_diag is only available on Linux")
+)
+
+// implementation memo:
+// https://github.com/torvalds/linux/blob/dcc0b49040c70ad827a7f3d58a21b01fdb14e749/include/uapi/linux/inet_diag.h
+// https://github.com/torvalds/linux/blob/dcc0b49040c70ad827a7f3d58a21b01fdb14e749/net/sctp/diag.c
+const (
+	netlinkSockDiag = 4 // NETLINK_SOCK_DIAG
+
+	nlmsgError       = 2  // NLMSG_ERROR
+	nlmsgDone        = 3  // NLMSG_DONE
+	sockDiagByFamily = 20 // SOCK_DIAG_BY_FAMILY
+
+	nlmFRequest = 0x1   // NLM_F_REQUEST
+	nlmFDump    = 0x300 // NLM_F_ROOT | NLM_F_MATCH
+
+	afInet      = 2  // AF_INET
+	afInet6     = 10 // AF_INET6
+	ipprotoSCTP = 132
+
+	inetDiagSKMemInfo = 7  // INET_DIAG_SKMEMINFO
+	inetDiagInfo      = 2  // INET_DIAG_INFO; carries struct sctp_info
+	inetDiagLocals    = 12 // INET_DIAG_LOCALS; an array of struct sockaddr_storage
+	inetDiagPeers     = 13 // INET_DIAG_PEERS; an array of struct sockaddr_storage
+
+	nlmsghdrLen        = 16
+	inetDiagReqV2Len   = 56
+	inetDiagMsgLen     = 72
+	nlattrHeaderLen    = 4
+	sockaddrStorageLen = 128
+	sctpInfoLen        = 368
+
+	enoent = 2 // the kernel replies ENOENT if the sctp_diag module is not loaded
+
+	allStates = 0xffffffff
+)
+
+// skMemInfo indexes of SK_MEMINFO_*.
+const (
+	skMemInfoRmemAlloc = iota
+	skMemInfoRcvbuf
+	skMemInfoWmemAlloc
+	skMemInfoSndbuf
+	skMemInfoFwdAlloc
+	skMemInfoWmemQueued
+)
+
+// nativeEndian is the byte order of the netlink messages, which is the host's one.
+var nativeEndian = func() binary.ByteOrder {
+	v := uint16(1)
+	if *(*byte)(unsafe.Pointer(&v)) == 1 {
+		return binary.LittleEndian
+	}
+	return binary.BigEndian
+}()
+
+// encodeRequest encodes a netlink message of inet_diag_req_v2 that dumps all the SCTP sockets of the address family,
+// with sctp_info and the memory information.
+func encodeRequest(family uint8, seq uint32) []byte {
+	b := make([]byte, nlmsghdrLen+inetDiagReqV2Len)
+
+	// struct nlmsghdr
+	nativeEndian.PutUint32(b[0:4], uint32(len(b)))
+	nativeEndian.PutUint16(b[4:6], sockDiagByFamily)
+	nativeEndian.PutUint16(b[6:8], nlmFRequest|nlmFDump)
+	nativeEndian.PutUint32(b[8:12], seq)
+	nativeEndian.PutUint32(b[12:16], 0) // pid; the kernel fills it
+
+	// struct inet_diag_req_v2
+	req := b[nlmsghdrLen:]
+	req[0] = family
+	req[1] = ipprotoSCTP
+	req[2] = 1<<(inetDiagInfo-1) | 1<<(inetDiagSKMemInfo-1) // idiag_ext
+	nativeEndian.PutUint32(req[4:8], allStates)
+	// the socket ID (req[8:56]) is left zero not to filter any sockets
+
+	return b
+}
+
+// parseMessages splits the netlink messages of a datagram and returns the payloads of SOCK_DIAG_BY_FAMILY.
+// It returns true as the second value when the dump has completed with NLMSG_DONE.
+func parseMessages(b []byte) ([][]byte, bool, error) {
+	payloads := make([][]byte, 0)
+	for len(b) > 0 {
+		if len(b) < nlmsghdrLen {
+			return nil, false, fmt.Errorf("nlmsghdr: %w", ErrInvalidMessage)
+		}
+		length := int(nativeEndian.Uint32(b[0:4]))
+		typ := nativeEndian.Uint16(b[4:6])
+		if length < nlmsghdrLen || length > len(b) {
+			return nil, false, fmt.Errorf("nlmsg_len %d: %w", length, ErrInvalidMessage)
+		}
+		payload := b[nlmsghdrLen:length]
+
+		switch typ {
+		case nlmsgDone:
+			return payloads, true, nil
+		case nlmsgError:
+			if len(payload) < 4 {
+				return nil, false, fmt.Errorf("nlmsgerr: %w", ErrInvalidMessage)
+			}
+			errno := -int32(nativeEndian.Uint32(payload[0:4]))
+			if errno == enoent {
+				return nil, false, fmt.Errorf("sock_diag: %w", parser.ErrSCTPNotAvailable)
+			}
+			return nil, false, fmt.Errorf("errno %d: %w", errno, ErrDumpFailed)
+		case sockDiagByFamily:
+			payloads = append(payloads, payload)
+		}
+
+		b = b[minInt(nlmsgAlign(length), len(b)):]
+	}
+	return payloads, false, nil
+}
+
+// diagMsg is a decoded struct inet_diag_msg with its attributes.
+type diagMsg struct {
+	family  uint8
+	state   uint8
+	sport   uint16
+	dport   uint16
+	cookie  uint64
+	rqueue  uint32
+	wqueue  uint32
+	uid     uint32
+	inode   uint32
+	attrs   map[uint16][]byte
+	hasPeer bool
+}
+
+func decodeDiagMsg(b []byte) (*diagMsg, error) {
+	if len(b) < inetDiagMsgLen {
+		return nil, fmt.Errorf("inet_diag_msg: %w", ErrInvalidMessage)
+	}
+
+	msg := &diagMsg{
+		family: b[0],
+		state:  b[1],
+		sport:  binary.BigEndian.Uint16(b[4:6]),
+		dport:  binary.BigEndian.Uint16(b[6:8]),
+		cookie: uint64(nativeEndian.Uint32(b[44:48])) | uint64(nativeEndian.Uint32(b[48:52]))<<32,
+		rqueue: nativeEndian.Uint32(b[56:60]),
+		wqueue: nativeEndian.Uint32(b[60:64]),
+		uid:    nativeEndian.Uint32(b[64:68]),
+		inode:  nativeEndian.Uint32(b[68:72]),
+		attrs:  make(map[uint16][]byte),
+	}
+
+	b = b[inetDiagMsgLen:]
+	for len(b) > 0 {
+		if len(b) < nlattrHeaderLen {
+			return nil, fmt.Errorf("nlattr: %w", ErrInvalidMessage)
+		}
+		length := int(nativeEndian.Uint16(b[0:2]))
+		typ := nativeEndian.Uint16(b[2:4]) & 0x3fff // without NLA_F_NESTED and NLA_F_NET_BYTEORDER
+		if length < nlattrHeaderLen || length > len(b) {
+			return nil, fmt.Errorf("nla_len %d: %w", length, ErrInvalidMessage)
+		}
+		msg.attrs[typ] = b[nlattrHeaderLen:length]
+
+		b = b[minInt(nlmsgAlign(length), len(b)):] // the last attribute may not be padded
+	}
+	_, msg.hasPeer = msg.attrs[inetDiagPeers]
+
+	return msg, nil
+}
+
+// decodeSockaddrs decodes an array of struct sockaddr_storage.
+// It returns the addresses in the same notation as the proc files, and the parsed ones.
+func decodeSockaddrs(b []byte) ([]string, []netip.Addr, error) {
+	if len(b)%sockaddrStorageLen != 0 {
+		return nil, nil, fmt.Errorf("sockaddr_storage array of %d bytes: %w", len(b), ErrInvalidMessage)
+	}
+
+	addrs := make([]string, 0, len(b)/sockaddrStorageLen)
+	ips := make([]netip.Addr, 0, len(b)/sockaddrStorageLen)
+	for ; len(b) > 0; b = b[sockaddrStorageLen:] {
+		ip, ok := decodeSockaddr(b[:sockaddrStorageLen])
+		if !ok {
+			return nil, nil, fmt.Errorf("sockaddr family %d: %w", nativeEndian.Uint16(b[0:2]), ErrInvalidMessage)
+		}
+		addrs = append(addrs, formatAddr(ip))
+		ips = append(ips, ip)
+	}
+	return addrs, ips, nil
+}
+
+// decodeSockaddr decodes struct sockaddr_in or struct sockaddr_in6.
+func decodeSockaddr(b []byte) (netip.Addr, bool) {
+	switch nativeEndian.Uint16(b[0:2]) {
+	case afInet:
+		return netip.AddrFrom4(*(*[4]byte)(b[4:8])), true
+	case afInet6:
+		return netip.AddrFrom16(*(*[16]byte)(b[8:24])), true
+	default:
+		return netip.Addr{}, false
+	}
+}
+
+// formatAddr formats the address as the kernel does in the proc files; IPv6 addresses are not compressed (`%pI6`).
+func formatAddr(addr netip.Addr) string {
+	if addr.Is4() {
+		return addr.String()
+	}
+	return addr.StringExpanded()
+}
+
+// decodeMemInfo decodes the array of SK_MEMINFO_* values. Older kernels have fewer values.
+func decodeMemInfo(b []byte) ([]uint32, error) {
+	if len(b) < (skMemInfoWmemQueued+1)*4 {
+		return nil, fmt.Errorf("sk_meminfo of %d bytes: %w", len(b), ErrInvalidMessage)
+	}
+	values := make([]uint32, len(b)/4)
+	for i := range values {
+		values[i] = nativeEndian.Uint32(b[i*4 : i*4+4])
+	}
+	return values, nil
+}
+
+// decodeSCTPInfo decodes struct sctp_info.
+func decodeSCTPInfo(b []byte) (*Info, error) {
+	if len(b) < sctpInfoLen {
+		return nil, fmt.Errorf("sctp_info of %d bytes: %w", len(b), ErrInvalidMessage)
+	}
+
+	u16 := func(off int) uint16 { return nativeEndian.Uint16(b[off : off+2]) }
+	u32 := func(off int) uint32 { return nativeEndian.Uint32(b[off : off+4]) }
+	u64 := func(off int) uint64 { return nativeEndian.Uint64(b[off : off+8]) }
+
+	info := &Info{
+		Tag:                  u32(0),
+		State:                parser.AssocState(u32(4)),
+		Rwnd:                 u32(8),
+		UnackData:            u16(12),
+		PendData:             u16(14),
+		InStreams:            u16(16),
+		OutStreams:           u16(18),
+		FragmentationPoint:   u32(20),
+		InQueue:              u32(24),
+		OutQueue:             u32(28),
+		OverallError:         u32(32),
+		MaxBurst:             u32(36),
+		MaxSeg:               u32(40),
+		PeerRwnd:             u32(44),
+		PeerTag:              u32(48),
+		PeerCapable:          b[52],
+		PeerSack:             b[53],
+		ISacks:               u64(56),
+		OSacks:               u64(64),
+		OPackets:             u64(72),
+		IPackets:             u64(80),
+		RtxChunks:            u64(88),
+		OutOfSeqTSNs:         u64(96),
+		IDupChunks:           u64(104),
+		GapCount:             u64(112),
+		OUnorderedDataChunks: u64(120),
+		IUnorderedDataChunks: u64(128),
+		OOrderedDataChunks:   u64(136),
+		IOrderedDataChunks:   u64(144),
+		OCtrlChunks:          u64(152),
+		ICtrlChunks:          u64(160),
+		Primary: PrimaryPathInfo{
+			State:             parser.TransportState(int32(u32(296))),
+			Cwnd:              u32(300),
+			SRTT:              u32(304),
+			RTO:               u32(308),
+			HBInterval:        u32(312),
+			PathMaxRxt:        u32(316),
+			SackDelay:         u32(320),
+			SackFreq:          u32(324),
+			Ssthresh:          u32(328),
+			PartialBytesAcked: u32(332),
+			FlightSize:        u32(336),
+			Error:             u16(340),
+		},
+		Socket: SocketInfo{
+			Autoclose:        u32(344),
+			AdaptationInd:    u32(348),
+			PDPoint:          u32(352),
+			NoDelay:          b[356] != 0,
+			DisableFragments: b[357] != 0,
+			V4Mapped:         b[358] != 0,
+			FragInterleave:   b[359],
+			Type:             parser.SocketType(u32(360)),
+		},
+	}
+	if addr, ok := decodeSockaddr(b[168 : 168+sockaddrStorageLen]); ok {
+		info.Primary.Addr = formatAddr(addr)
+	}
+
+	return info, nil
+}
+
+func nlmsgAlign(length int) int {
+	return (length + 3) &^ 3
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,130 @@
+package sockdiag
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	parser "github.com/moznion/go-sctp-proc-parser"
+	"github.com/stretchr/testify/assert"
+)
+
+// readDatagrams reads the datagrams of a hex dump in testdata; each datagram starts with a `# datagram` line.
+// The fixtures are captured on a little endian host, so the tests that use them are skipped on big endian hosts.
+func readDatagrams(t *testing.T, name string) [][]byte {
+	t.Helper()
+	if nativeEndian != binary.LittleEndian {
+		t.Skip("the fixtures are little endian")
+	}
+
+	contents, err := os.ReadFile(filepath.Join("testdata", name))
+	assert.NoError(t, err)
+
+	datagrams := make([][]byte, 0)
+	var current strings.Builder
+	flush := func() {
+		if current.Len() <= 0 {
+			return
+		}
+		b, err := hex.DecodeString(current.String())
+		assert.NoError(t, err)
+		datagrams = append(datagrams, b)
+		current.Reset()
+	}
+	for _, line := range strings.Split(string(contents), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "# datagram" {
+			flush()
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		current.WriteString(line)
+	}
+	flush()
+
+	return datagrams
+}
+
+func TestEncodeRequest(t *testing.T) {
+	b := encodeRequest(afInet6, 7)
+	assert.Len(t, b, 72)
+
+	assert.EqualValues(t, 72, nativeEndian.Uint32(b[0:4]))
+	assert.EqualValues(t, sockDiagByFamily, nativeEndian.Uint16(b[4:6]))
+	assert.EqualValues(t, 0x301, nativeEndian.Uint16(b[6:8]))
+	assert.EqualValues(t, 7, nativeEndian.Uint32(b[8:12]))
+
+	assert.Equal(t, []byte{afInet6, ipprotoSCTP, 0x42, 0}, b[16:20])
+	assert.EqualValues(t, uint32(0xffffffff), nativeEndian.Uint32(b[20:24]))
+	assert.Equal(t, make([]byte, 48), b[24:])
+}
+
+func TestParseMessages(t *testing.T) {
+	datagrams := readDatagrams(t, "dump_inet.hex")
+	assert.Len(t, datagrams, 2)
+
+	payloads, done, err := parseMessages(datagrams[0])
+	assert.NoError(t, err)
+	assert.False(t, done)
+	assert.Len(t, payloads, 4)
+
+	payloads, done, err = parseMessages(datagrams[1])
+	assert.NoError(t, err)
+	assert.True(t, done)
+	assert.Len(t, payloads, 0)
+}
+
+func TestParseMessages_Error(t *testing.T) {
+	datagrams := readDatagrams(t, "dump_enoent.hex")
+
+	_, _, err := parseMessages(datagrams[0])
+	assert.ErrorIs(t, err, parser.ErrSCTPNotAvailable)
+
+	eperm := append([]byte(nil), datagrams[0]...)
+	nativeEndian.PutUint32(eperm[16:20], uint32(0xffffffff)) // -EPERM
+	_, _, err = parseMessages(eperm)
+	assert.ErrorIs(t, err, ErrDumpFailed)
+	assert.EqualError(t, err, "errno 1: sock_diag dump failed")
+}
+
+func TestParseMessages_Invalid(t *testing.T) {
+	datagrams := readDatagrams(t, "dump_inet.hex")
+
+	_, _, err := parseMessages(datagrams[0][:10])
+	assert.ErrorIs(t, err, ErrInvalidMessage)
+
+	_, _, err = parseMessages(datagrams[0][:100]) // truncated in the middle of the first message
+	assert.ErrorIs(t, err, ErrInvalidMessage)
+}
+
+func TestDecodeDiagMsg_Invalid(t *testing.T) {
+	datagrams := readDatagrams(t, "dump_inet.hex")
+	payloads, _, err := parseMessages(datagrams[0])
+	assert.NoError(t, err)
+
+	_, err = decodeDiagMsg(payloads[0][:inetDiagMsgLen-1])
+	assert.ErrorIs(t, err, ErrInvalidMessage)
+
+	_, err = decodeDiagMsg(payloads[0][:inetDiagMsgLen+2]) // truncated attribute header
+	assert.ErrorIs(t, err, ErrInvalidMessage)
+
+	_, err = decodeDiagMsg(payloads[0][:inetDiagMsgLen+20]) // truncated attribute
+	assert.ErrorIs(t, err, ErrInvalidMessage)
+
+	_, _, err = decodeSockaddrs(make([]byte, sockaddrStorageLen-1))
+	assert.ErrorIs(t, err, ErrInvalidMessage)
+
+	_, _, err = decodeSockaddrs(make([]byte, sockaddrStorageLen)) // AF_UNSPEC
+	assert.ErrorIs(t, err, ErrInvalidMessage)
+
+	_, err = decodeMemInfo(make([]byte, 20))
+	assert.ErrorIs(t, err, ErrInvalidMessage)
+
+	_, err = decodeSCTPInfo(make([]byte, sctpInfoLen-1))
+	assert.ErrorIs(t, err, ErrInvalidMessage)
+}
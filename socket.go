@@ -0,0 +1,190 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrUnknownSocketType  = errors.New("unknown socket type")
+	ErrUnknownSocketState = errors.New("unknown socket state")
+)
+
+// SocketType represents the style of an SCTP socket; this corresponds to `enum sctp_socket_type` of the kernel,
+// which is what the kernel emits in the STY column.
+type SocketType int64
+
+const (
+	// SocketTypeOneToMany is the one-to-many style (SCTP_SOCKET_UDP); a socket that is opened with SOCK_SEQPACKET.
+	SocketTypeOneToMany SocketType = 0
+	// SocketTypeOneToManyHighBandwidth is SCTP_SOCKET_UDP_HIGH_BANDWIDTH; the kernel doesn't use this for now.
+	SocketTypeOneToManyHighBandwidth SocketType = 1
+	// SocketTypeOneToOne is the one-to-one style (SCTP_SOCKET_TCP); a socket that is opened with SOCK_STREAM.
+	SocketTypeOneToOne SocketType = 2
+)
+
+var socketTypeNames = map[SocketType]string{
+	SocketTypeOneToMany:              "ONE_TO_MANY",
+	SocketTypeOneToManyHighBandwidth: "ONE_TO_MANY_HIGH_BANDWIDTH",
+	SocketTypeOneToOne:               "ONE_TO_ONE",
+}
+
+// socketTypeAliases are the other names of the socket types that ParseSocketType accepts.
+var socketTypeAliases = map[string]SocketType{
+	"UDP":                SocketTypeOneToMany,
+	"SEQPACKET":          SocketTypeOneToMany,
+	"UDP_HIGH_BANDWIDTH": SocketTypeOneToManyHighBandwidth,
+	"TCP":                SocketTypeOneToOne,
+	"STREAM":             SocketTypeOneToOne,
+}
+
+// String returns the name of the socket type, e.g. `ONE_TO_MANY`.
+func (t SocketType) String() string {
+	if name, ok := socketTypeNames[t]; ok {
+		return name
+	}
+	return "SocketType(" + strconv.FormatInt(int64(t), 10) + ")"
+}
+
+// IsOneToMany returns whether the socket is one-to-many style.
+func (t SocketType) IsOneToMany() bool {
+	return t == SocketTypeOneToMany || t == SocketTypeOneToManyHighBandwidth
+}
+
+// IsOneToOne returns whether the socket is one-to-one style.
+func (t SocketType) IsOneToOne() bool {
+	return t == SocketTypeOneToOne
+}
+
+// MarshalText encodes the socket type as its name. A socket type that has no name is encoded as a decimal number.
+func (t SocketType) MarshalText() ([]byte, error) {
+	if name, ok := socketTypeNames[t]; ok {
+		return []byte(name), nil
+	}
+	return []byte(strconv.FormatInt(int64(t), 10)), nil
+}
+
+// UnmarshalText decodes the socket type from its name or a decimal number.
+func (t *SocketType) UnmarshalText(text []byte) error {
+	socketType, err := ParseSocketType(string(text))
+	if err != nil {
+		if n, numErr := strconv.ParseInt(string(text), 10, 64); numErr == nil {
+			*t = SocketType(n)
+			return nil
+		}
+		return err
+	}
+	*t = socketType
+	return nil
+}
+
+// ParseSocketType returns the SocketType that has the name. The name is case-insensitive.
+// The kernel's names (`UDP`, `UDP_HIGH_BANDWIDTH` and `TCP`, optionally with the `SCTP_SOCKET_` prefix)
+// and the socket types (`SEQPACKET` and `STREAM`, optionally with the `SOCK_` prefix) are also accepted.
+func ParseSocketType(name string) (SocketType, error) {
+	name = strings.ToUpper(name)
+	name = strings.TrimPrefix(name, "SCTP_SOCKET_")
+	name = strings.TrimPrefix(name, "SOCK_")
+	name = strings.ReplaceAll(name, "-", "_")
+	for socketType, socketTypeName := range socketTypeNames {
+		if socketTypeName == name {
+			return socketType, nil
+		}
+	}
+	if socketType, ok := socketTypeAliases[name]; ok {
+		return socketType, nil
+	}
+	return 0, fmt.Errorf("%q: %w", name, ErrUnknownSocketType)
+}
+
+// SocketState represents the state of a socket; this corresponds to sk_state of the kernel.
+// SCTP sockets reuse the TCP states (e.g. SCTP_SS_LISTENING is TCP_LISTEN).
+type SocketState int64
+
+const (
+	SocketStateEstablished SocketState = 1
+	SocketStateSynSent     SocketState = 2
+	SocketStateSynRecv     SocketState = 3
+	SocketStateFinWait1    SocketState = 4
+	SocketStateFinWait2    SocketState = 5
+	SocketStateTimeWait    SocketState = 6
+	SocketStateClose       SocketState = 7
+	SocketStateCloseWait   SocketState = 8
+	SocketStateLastAck     SocketState = 9
+	SocketStateListen      SocketState = 10
+	SocketStateClosing     SocketState = 11
+	SocketStateNewSynRecv  SocketState = 12
+)
+
+var socketStateNames = map[SocketState]string{
+	SocketStateEstablished: "ESTABLISHED",
+	SocketStateSynSent:     "SYN_SENT",
+	SocketStateSynRecv:     "SYN_RECV",
+	SocketStateFinWait1:    "FIN_WAIT1",
+	SocketStateFinWait2:    "FIN_WAIT2",
+	SocketStateTimeWait:    "TIME_WAIT",
+	SocketStateClose:       "CLOSE",
+	SocketStateCloseWait:   "CLOSE_WAIT",
+	SocketStateLastAck:     "LAST_ACK",
+	SocketStateListen:      "LISTEN",
+	SocketStateClosing:     "CLOSING",
+	SocketStateNewSynRecv:  "NEW_SYN_RECV",
+}
+
+// String returns the name of the socket state, e.g. `LISTEN`.
+func (s SocketState) String() string {
+	if name, ok := socketStateNames[s]; ok {
+		return name
+	}
+	return "SocketState(" + strconv.FormatInt(int64(s), 10) + ")"
+}
+
+// IsListening returns whether the socket is listening (SCTP_SS_LISTENING).
+func (s SocketState) IsListening() bool {
+	return s == SocketStateListen
+}
+
+// IsEstablished returns whether the socket is established (SCTP_SS_ESTABLISHED).
+func (s SocketState) IsEstablished() bool {
+	return s == SocketStateEstablished
+}
+
+// IsClosed returns whether the socket is closed (SCTP_SS_CLOSED).
+func (s SocketState) IsClosed() bool {
+	return s == SocketStateClose
+}
+
+// MarshalText encodes the socket state as its name. A socket state that has no name is encoded as a decimal number.
+func (s SocketState) MarshalText() ([]byte, error) {
+	if name, ok := socketStateNames[s]; ok {
+		return []byte(name), nil
+	}
+	return []byte(strconv.FormatInt(int64(s), 10)), nil
+}
+
+// UnmarshalText decodes the socket state from its name or a decimal number.
+func (s *SocketState) UnmarshalText(text []byte) error {
+	state, err := ParseSocketState(string(text))
+	if err != nil {
+		if n, numErr := strconv.ParseInt(string(text), 10, 64); numErr == nil {
+			*s = SocketState(n)
+			return nil
+		}
+		return err
+	}
+	*s = state
+	return nil
+}
+
+// ParseSocketState returns the SocketState that has the name. The name is case-insensitive and may have the `TCP_` prefix.
+func ParseSocketState(name string) (SocketState, error) {
+	name = strings.TrimPrefix(strings.ToUpper(name), "TCP_")
+	for state, stateName := range socketStateNames {
+		if stateName == name {
+			return state, nil
+		}
+	}
+	return 0, fmt.Errorf("%q: %w", name, ErrUnknownSocketState)
+}
@@ -0,0 +1,75 @@
+package parser
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSocketType(t *testing.T) {
+	assert.Equal(t, "ONE_TO_MANY", SocketTypeOneToMany.String())
+	assert.Equal(t, "ONE_TO_ONE", SocketTypeOneToOne.String())
+	assert.Equal(t, "SocketType(9)", SocketType(9).String())
+
+	assert.True(t, SocketTypeOneToMany.IsOneToMany())
+	assert.True(t, SocketTypeOneToManyHighBandwidth.IsOneToMany())
+	assert.False(t, SocketTypeOneToMany.IsOneToOne())
+	assert.True(t, SocketTypeOneToOne.IsOneToOne())
+	assert.False(t, SocketTypeOneToOne.IsOneToMany())
+}
+
+func TestParseSocketType(t *testing.T) {
+	tests := map[string]SocketType{
+		"ONE_TO_MANY":        SocketTypeOneToMany,
+		"one-to-one":         SocketTypeOneToOne,
+		"SCTP_SOCKET_UDP":    SocketTypeOneToMany,
+		"tcp":                SocketTypeOneToOne,
+		"SOCK_SEQPACKET":     SocketTypeOneToMany,
+		"stream":             SocketTypeOneToOne,
+		"UDP_HIGH_BANDWIDTH": SocketTypeOneToManyHighBandwidth,
+	}
+	for name, expected := range tests {
+		socketType, err := ParseSocketType(name)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, socketType, name)
+	}
+
+	_, err := ParseSocketType("DGRAM")
+	assert.ErrorIs(t, err, ErrUnknownSocketType)
+}
+
+func TestSocketState(t *testing.T) {
+	assert.Equal(t, "LISTEN", SocketStateListen.String())
+	assert.Equal(t, "ESTABLISHED", SocketStateEstablished.String())
+	assert.Equal(t, "SocketState(99)", SocketState(99).String())
+
+	assert.True(t, SocketStateListen.IsListening())
+	assert.False(t, SocketStateEstablished.IsListening())
+	assert.True(t, SocketStateEstablished.IsEstablished())
+	assert.True(t, SocketStateClose.IsClosed())
+}
+
+func TestParseSocketState(t *testing.T) {
+	state, err := ParseSocketState("tcp_listen")
+	assert.NoError(t, err)
+	assert.Equal(t, SocketStateListen, state)
+
+	_, err = ParseSocketState("COOKIE_WAIT")
+	assert.ErrorIs(t, err, ErrUnknownSocketState)
+}
+
+func TestSocketTypeAndState_JSON(t *testing.T) {
+	type socket struct {
+		Type  SocketType  `json:"type"`
+		State SocketState `json:"state"`
+	}
+
+	encoded, err := json.Marshal(&socket{Type: SocketTypeOneToOne, State: SocketStateListen})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"type":"ONE_TO_ONE","state":"LISTEN"}`, string(encoded))
+
+	var decoded socket
+	assert.NoError(t, json.Unmarshal([]byte(`{"type":"ONE_TO_MANY","state":"7"}`), &decoded))
+	assert.Equal(t, socket{Type: SocketTypeOneToMany, State: SocketStateClose}, decoded)
+}
@@ -0,0 +1,184 @@
+package parser
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Source provides the SCTP records regardless of where they come from; e.g. the proc filesystem (ProcFS),
+// the captured files (DirSource), the memory (MemorySource) or sock_diag (see sockdiag package).
+//
+// The implementations return ErrSCTPNotAvailable (possibly wrapped) if SCTP is not available at the origin,
+// and the context's error if the context is done before reading.
+type Source interface {
+	// Assocs returns the associations, as `/proc/net/sctp/assocs` has.
+	Assocs(ctx context.Context) ([]*Assoc, error)
+	// Endpoints returns the endpoints, as `/proc/net/sctp/eps` has.
+	Endpoints(ctx context.Context) ([]*EPS, error)
+	// Paths returns the paths to the remote addresses of the associations, as `/proc/net/sctp/remaddr` has.
+	Paths(ctx context.Context) ([]*Remaddr, error)
+	// SNMP returns the SCTP MIB counters, as `/proc/net/sctp/snmp` has.
+	SNMP(ctx context.Context) (*SNMP, error)
+}
+
+// Assocs implements Source; it reads and parses `net/sctp/assocs` file.
+func (fs ProcFS) Assocs(ctx context.Context) ([]*Assoc, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return fs.ReadAssocs()
+}
+
+// Endpoints implements Source; it reads and parses `net/sctp/eps` file.
+func (fs ProcFS) Endpoints(ctx context.Context) ([]*EPS, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return fs.ReadEPS()
+}
+
+// Paths implements Source; it reads and parses `net/sctp/remaddr` file.
+func (fs ProcFS) Paths(ctx context.Context) ([]*Remaddr, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return fs.ReadRemaddr()
+}
+
+// SNMP implements Source; it reads and parses `net/sctp/snmp` file.
+func (fs ProcFS) SNMP(ctx context.Context) (*SNMP, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return fs.ReadSNMP()
+}
+
+// DirSource is a Source that reads the files captured from `/proc/net/sctp` directory; e.g. by `cp -r /proc/net/sctp <dir>`.
+// The directory has assocs, eps, remaddr and snmp files. Unlike ProcFS, a missing file is an error of os.ErrNotExist.
+type DirSource struct {
+	dir string
+}
+
+// NewDirSource returns a new DirSource that reads the files in the directory.
+func NewDirSource(dir string) DirSource {
+	return DirSource{dir: dir}
+}
+
+// Dir returns the directory of the captured files.
+func (s DirSource) Dir() string {
+	return s.dir
+}
+
+// Assocs implements Source; it reads and parses assocs file.
+func (s DirSource) Assocs(ctx context.Context) ([]*Assoc, error) {
+	return readCapturedFile(ctx, s, "assocs", ParseAssocs)
+}
+
+// Endpoints implements Source; it reads and parses eps file.
+func (s DirSource) Endpoints(ctx context.Context) ([]*EPS, error) {
+	return readCapturedFile(ctx, s, "eps", ParseEPS)
+}
+
+// Paths implements Source; it reads and parses remaddr file.
+func (s DirSource) Paths(ctx context.Context) ([]*Remaddr, error) {
+	return readCapturedFile(ctx, s, "remaddr", ParseRemaddr)
+}
+
+// SNMP implements Source; it reads and parses snmp file.
+func (s DirSource) SNMP(ctx context.Context) (*SNMP, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	f, err := os.Open(filepath.Join(s.dir, "snmp"))
+	if err != nil {
+		return nil, err
+	}
+	return parseSNMPFile(f)
+}
+
+func readCapturedFile[T any](ctx context.Context, s DirSource, name string, parse func(*bufio.Scanner, ...bool) ([]T, error)) ([]T, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	f, err := os.Open(filepath.Join(s.dir, name))
+	if err != nil {
+		return nil, err
+	}
+	return parseRecordsFile(f, parse)
+}
+
+// MemorySource is a Source that returns the records in the memory; e.g. a fake in tests, or the records that have been decoded elsewhere.
+// The records can be replaced with Set while the source is in use.
+//
+// The returned records are not copied; annotating them (e.g. with SocketOwners.AnnotateAssocs) changes the records in the source.
+type MemorySource struct {
+	mu       sync.RWMutex
+	assocs   []*Assoc
+	eps      []*EPS
+	remaddrs []*Remaddr
+	snmp     *SNMP
+}
+
+// NewMemorySource returns a new MemorySource of the records. A nil snmp results in zero counters.
+func NewMemorySource(assocs []*Assoc, eps []*EPS, remaddrs []*Remaddr, snmp *SNMP) *MemorySource {
+	s := &MemorySource{}
+	s.Set(assocs, eps, remaddrs, snmp)
+	return s
+}
+
+// Set replaces the records. A nil snmp results in zero counters.
+func (s *MemorySource) Set(assocs []*Assoc, eps []*EPS, remaddrs []*Remaddr, snmp *SNMP) {
+	if snmp == nil {
+		snmp = &SNMP{Unknown: make(map[string]uint64)}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.assocs = assocs
+	s.eps = eps
+	s.remaddrs = remaddrs
+	s.snmp = snmp
+}
+
+// Assocs implements Source.
+func (s *MemorySource) Assocs(ctx context.Context) ([]*Assoc, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.assocs, nil
+}
+
+// Endpoints implements Source.
+func (s *MemorySource) Endpoints(ctx context.Context) ([]*EPS, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.eps, nil
+}
+
+// Paths implements Source.
+func (s *MemorySource) Paths(ctx context.Context) ([]*Remaddr, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.remaddrs, nil
+}
+
+// SNMP implements Source.
+func (s *MemorySource) SNMP(ctx context.Context) (*SNMP, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.snmp, nil
+}
@@ -0,0 +1,122 @@
+package parser
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProcFS_Source(t *testing.T) {
+	root := t.TempDir()
+	writeProcFiles(t, root, map[string]string{
+		"net/sctp/assocs": ` ASSOC     SOCK   STY SST ST HBKT ASSOC-ID TX_QUEUE RX_QUEUE UID INODE LPORT RPORT LADDRS <-> RADDRS HBINT INS OUTS MAXRT T1X T2X RTXC wmema wmemq sndbuf rcvbuf
+       0        0 2   1   3  0      60        0      496       0 188897 12345 54321  127.0.0.1 <-> *127.0.0.2 	   30000 65535 65535   10    0    0        0        1        0   212992   212992
+`,
+		"net/sctp/eps": ` ENDPT     SOCK   STY SST HBKT LPORT   UID INODE LADDRS
+       0        0 2   10  24   12345     0 188897 127.0.0.1 
+`,
+		"net/sctp/remaddr": `ADDR ASSOC_ID HB_ACT RTO MAX_PATH_RTX REM_ADDR_RTX START STATE
+127.0.0.2  60 1 1000 5 0 0 2
+`,
+		"net/sctp/snmp": `SctpCurrEstab                   	1
+`,
+	})
+
+	var source Source = NewProcFS(root)
+	ctx := context.Background()
+
+	assocs, err := source.Assocs(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, assocs, 1)
+	eps, err := source.Endpoints(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, eps, 1)
+	paths, err := source.Paths(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, paths, 1)
+	snmp, err := source.SNMP(ctx)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, snmp.CurrEstab)
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = source.Assocs(canceled)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, err = NewProcFS(t.TempDir()).Paths(ctx)
+	assert.ErrorIs(t, err, ErrSCTPNotAvailable)
+}
+
+func TestDirSource(t *testing.T) {
+	dir := t.TempDir()
+	writeProcFiles(t, dir, map[string]string{
+		"assocs": ` ASSOC     SOCK   STY SST ST HBKT ASSOC-ID TX_QUEUE RX_QUEUE UID INODE LPORT RPORT LADDRS <-> RADDRS HBINT INS OUTS MAXRT T1X T2X RTXC
+       0        0 2   1   3  0      60        0      496       0 188897 12345 54321  127.0.0.1 <-> *127.0.0.2 	   30000 65535 65535   10    0    0        0
+`,
+		"eps": ` ENDPT     SOCK   STY SST HBKT LPORT   UID INODE LADDRS
+`,
+		"remaddr": `ADDR ASSOC_ID HB_ACT RTO MAX_PATH_RTX REM_ADDR_RTX START STATE
+127.0.0.2  60 1 1000 5 0 0 2
+`,
+		"snmp": `SctpCurrEstab                   	1
+SctpActiveEstabs                	3
+`,
+	})
+
+	source := NewDirSource(dir)
+	assert.Equal(t, dir, source.Dir())
+
+	snapshot, err := ReadSnapshotFrom(context.Background(), source, 1)
+	assert.NoError(t, err)
+	assert.True(t, snapshot.Consistent)
+	assert.Len(t, snapshot.EPS, 0)
+	view, ok := snapshot.Lookup(60)
+	assert.True(t, ok)
+	assert.False(t, view.Assoc.Has(AssocFieldWmema)) // captured from an older kernel
+	assert.EqualValues(t, 1000, view.PrimaryPath().RTO)
+
+	snmp, err := source.SNMP(context.Background())
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, snmp.ActiveEstabs)
+
+	_, err = NewDirSource(t.TempDir()).Endpoints(context.Background())
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	writeProcFiles(t, dir, map[string]string{"eps": "ENDPT\n0 1\n"})
+	_, err = source.Endpoints(context.Background())
+	assert.ErrorIs(t, err, ErrInvalidEPSFormat)
+}
+
+func TestMemorySource(t *testing.T) {
+	assocs := []*Assoc{{AssocId: 1, RAddrs: []string{"10.0.0.2"}}}
+	remaddrs := []*Remaddr{{AssocID: 1, Addr: "10.0.0.2"}}
+	source := NewMemorySource(assocs, nil, remaddrs, nil)
+	ctx := context.Background()
+
+	snapshot, err := ReadSnapshotFrom(ctx, source, 1)
+	assert.NoError(t, err)
+	assert.True(t, snapshot.Consistent)
+	assert.Same(t, assocs[0], snapshot.Associations[0].Assoc)
+
+	snmp, err := source.SNMP(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, &SNMP{Unknown: map[string]uint64{}}, snmp)
+
+	source.Set(nil, []*EPS{{LPort: 3868}}, nil, &SNMP{CurrEstab: 2})
+	got, err := source.Assocs(ctx)
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+	eps, err := source.Endpoints(ctx)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3868, eps[0].LPort)
+	snmp, err = source.SNMP(ctx)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, snmp.CurrEstab)
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = ReadSnapshotFrom(canceled, source, 1)
+	assert.ErrorIs(t, err, context.Canceled)
+}
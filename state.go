@@ -0,0 +1,144 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrUnknownAssocState     = errors.New("unknown assoc state")
+	ErrUnknownTransportState = errors.New("unknown transport state")
+)
+
+// AssocState represents the state of an SCTP association; this corresponds to `enum sctp_state` (sctp_state_t) of the kernel.
+type AssocState int64
+
+const (
+	AssocStateClosed           AssocState = 0
+	AssocStateCookieWait       AssocState = 1
+	AssocStateCookieEchoed     AssocState = 2
+	AssocStateEstablished      AssocState = 3
+	AssocStateShutdownPending  AssocState = 4
+	AssocStateShutdownSent     AssocState = 5
+	AssocStateShutdownReceived AssocState = 6
+	AssocStateShutdownAckSent  AssocState = 7
+)
+
+var assocStateNames = map[AssocState]string{
+	AssocStateClosed:           "CLOSED",
+	AssocStateCookieWait:       "COOKIE_WAIT",
+	AssocStateCookieEchoed:     "COOKIE_ECHOED",
+	AssocStateEstablished:      "ESTABLISHED",
+	AssocStateShutdownPending:  "SHUTDOWN_PENDING",
+	AssocStateShutdownSent:     "SHUTDOWN_SENT",
+	AssocStateShutdownReceived: "SHUTDOWN_RECEIVED",
+	AssocStateShutdownAckSent:  "SHUTDOWN_ACK_SENT",
+}
+
+// String returns the name of the state, e.g. `ESTABLISHED`.
+func (s AssocState) String() string {
+	if name, ok := assocStateNames[s]; ok {
+		return name
+	}
+	return "AssocState(" + strconv.FormatInt(int64(s), 10) + ")"
+}
+
+// MarshalText encodes the state as its name. A state that has no name is encoded as a decimal number.
+func (s AssocState) MarshalText() ([]byte, error) {
+	if name, ok := assocStateNames[s]; ok {
+		return []byte(name), nil
+	}
+	return []byte(strconv.FormatInt(int64(s), 10)), nil
+}
+
+// UnmarshalText decodes the state from its name or a decimal number.
+func (s *AssocState) UnmarshalText(text []byte) error {
+	state, err := ParseAssocState(string(text))
+	if err != nil {
+		if n, numErr := strconv.ParseInt(string(text), 10, 64); numErr == nil {
+			*s = AssocState(n)
+			return nil
+		}
+		return err
+	}
+	*s = state
+	return nil
+}
+
+// ParseAssocState returns the AssocState that has the name. The name is case-insensitive and may have the `SCTP_STATE_` prefix.
+func ParseAssocState(name string) (AssocState, error) {
+	name = strings.TrimPrefix(strings.ToUpper(name), "SCTP_STATE_")
+	for state, stateName := range assocStateNames {
+		if stateName == name {
+			return state, nil
+		}
+	}
+	return 0, fmt.Errorf("%q: %w", name, ErrUnknownAssocState)
+}
+
+// TransportState represents the state of an SCTP transport (i.e. a path to a remote address);
+// this corresponds to `enum sctp_spinfo_state` of the kernel.
+type TransportState int64
+
+const (
+	TransportStateInactive    TransportState = 0
+	TransportStatePF          TransportState = 1 // potentially failed
+	TransportStateActive      TransportState = 2
+	TransportStateUnconfirmed TransportState = 3
+	TransportStateUnknown     TransportState = 0xffff
+)
+
+var transportStateNames = map[TransportState]string{
+	TransportStateInactive:    "INACTIVE",
+	TransportStatePF:          "PF",
+	TransportStateActive:      "ACTIVE",
+	TransportStateUnconfirmed: "UNCONFIRMED",
+	TransportStateUnknown:     "UNKNOWN",
+}
+
+// String returns the name of the state, e.g. `ACTIVE`.
+func (s TransportState) String() string {
+	if name, ok := transportStateNames[s]; ok {
+		return name
+	}
+	return "TransportState(" + strconv.FormatInt(int64(s), 10) + ")"
+}
+
+// MarshalText encodes the state as its name. A state that has no name is encoded as a decimal number.
+func (s TransportState) MarshalText() ([]byte, error) {
+	if name, ok := transportStateNames[s]; ok {
+		return []byte(name), nil
+	}
+	return []byte(strconv.FormatInt(int64(s), 10)), nil
+}
+
+// UnmarshalText decodes the state from its name or a decimal number.
+func (s *TransportState) UnmarshalText(text []byte) error {
+	state, err := ParseTransportState(string(text))
+	if err != nil {
+		if n, numErr := strconv.ParseInt(string(text), 10, 64); numErr == nil {
+			*s = TransportState(n)
+			return nil
+		}
+		return err
+	}
+	*s = state
+	return nil
+}
+
+// ParseTransportState returns the TransportState that has the name. The name is case-insensitive and may have the `SCTP_` prefix.
+// `POTENTIALLY_FAILED` is accepted as an alias of `PF`.
+func ParseTransportState(name string) (TransportState, error) {
+	name = strings.TrimPrefix(strings.ToUpper(name), "SCTP_")
+	if name == "POTENTIALLY_FAILED" {
+		return TransportStatePF, nil
+	}
+	for state, stateName := range transportStateNames {
+		if stateName == name {
+			return state, nil
+		}
+	}
+	return 0, fmt.Errorf("%q: %w", name, ErrUnknownTransportState)
+}
@@ -0,0 +1,72 @@
+package parser
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssocState_String(t *testing.T) {
+	assert.Equal(t, "CLOSED", AssocStateClosed.String())
+	assert.Equal(t, "ESTABLISHED", AssocStateEstablished.String())
+	assert.Equal(t, "SHUTDOWN_ACK_SENT", AssocStateShutdownAckSent.String())
+	assert.Equal(t, "AssocState(42)", AssocState(42).String())
+}
+
+func TestParseAssocState(t *testing.T) {
+	state, err := ParseAssocState("COOKIE_ECHOED")
+	assert.NoError(t, err)
+	assert.Equal(t, AssocStateCookieEchoed, state)
+
+	state, err = ParseAssocState("sctp_state_shutdown_pending")
+	assert.NoError(t, err)
+	assert.Equal(t, AssocStateShutdownPending, state)
+
+	_, err = ParseAssocState("LISTEN")
+	assert.ErrorIs(t, err, ErrUnknownAssocState)
+}
+
+func TestAssocState_JSON(t *testing.T) {
+	encoded, err := json.Marshal([]AssocState{AssocStateEstablished, AssocState(42)})
+	assert.NoError(t, err)
+	assert.Equal(t, `["ESTABLISHED","42"]`, string(encoded))
+
+	var decoded []AssocState
+	assert.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, []AssocState{AssocStateEstablished, AssocState(42)}, decoded)
+
+	assert.ErrorIs(t, json.Unmarshal([]byte(`["UNKNOWN"]`), &decoded), ErrUnknownAssocState)
+}
+
+func TestTransportState_String(t *testing.T) {
+	assert.Equal(t, "INACTIVE", TransportStateInactive.String())
+	assert.Equal(t, "PF", TransportStatePF.String())
+	assert.Equal(t, "ACTIVE", TransportStateActive.String())
+	assert.Equal(t, "UNCONFIRMED", TransportStateUnconfirmed.String())
+	assert.Equal(t, "UNKNOWN", TransportStateUnknown.String())
+	assert.Equal(t, "TransportState(42)", TransportState(42).String())
+}
+
+func TestParseTransportState(t *testing.T) {
+	state, err := ParseTransportState("active")
+	assert.NoError(t, err)
+	assert.Equal(t, TransportStateActive, state)
+
+	state, err = ParseTransportState("SCTP_POTENTIALLY_FAILED")
+	assert.NoError(t, err)
+	assert.Equal(t, TransportStatePF, state)
+
+	_, err = ParseTransportState("ESTABLISHED")
+	assert.ErrorIs(t, err, ErrUnknownTransportState)
+}
+
+func TestTransportState_JSON(t *testing.T) {
+	encoded, err := json.Marshal(map[string]TransportState{"state": TransportStateUnconfirmed})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"state":"UNCONFIRMED"}`, string(encoded))
+
+	var decoded map[string]TransportState
+	assert.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, TransportStateUnconfirmed, decoded["state"])
+}
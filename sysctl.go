@@ -0,0 +1,195 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidSysctlFormat = errors.New("invalid sysctl format")
+)
+
+// Sysctls represents the SCTP tunables; for example the files under `/proc/sys/net/sctp` directory.
+//
+// The tunables that the kernel doesn't have (e.g. the ones that have been introduced by newer kernels) are left as zero values;
+// whether a tunable exists can be checked with Raw.
+type Sysctls struct {
+	RTOInitial         time.Duration // rto_initial
+	RTOMin             time.Duration // rto_min
+	RTOMax             time.Duration // rto_max
+	RTOAlphaExpDivisor int64         // rto_alpha_exp_divisor
+	RTOBetaExpDivisor  int64         // rto_beta_exp_divisor
+	HBInterval         time.Duration // hb_interval
+	SACKTimeout        time.Duration // sack_timeout
+	ValidCookieLife    time.Duration // valid_cookie_life
+	MaxAutoclose       time.Duration // max_autoclose
+	ProbeInterval      time.Duration // plpmtud_probe_interval
+
+	MaxBurst              int64 // max_burst
+	AssociationMaxRetrans int64 // association_max_retrans
+	PathMaxRetrans        int64 // path_max_retrans
+	MaxInitRetransmits    int64 // max_init_retransmits
+	PFRetrans             int64 // pf_retrans
+	PSRetrans             int64 // ps_retrans
+	PFEnable              int64 // pf_enable
+	SndbufPolicy          int64 // sndbuf_policy
+	RcvbufPolicy          int64 // rcvbuf_policy
+	RwndUpdateShift       int64 // rwnd_update_shift
+	UDPPort               int64 // udp_port
+	EncapPort             int64 // encap_port
+
+	AddipEnable          bool // addip_enable
+	AddipNoauthEnable    bool // addip_noauth_enable
+	AuthEnable           bool // auth_enable
+	PrsctpEnable         bool // prsctp_enable
+	ReconfEnable         bool // reconf_enable
+	IntlEnable           bool // intl_enable
+	ECNEnable            bool // ecn_enable
+	CookiePreserveEnable bool // cookie_preserve_enable
+	L3mdevAccept         bool // l3mdev_accept
+
+	CookieHmacAlg string // cookie_hmac_alg
+
+	Mem  [3]int64 // sctp_mem; in pages
+	Rmem [3]int64 // sctp_rmem; in bytes
+	Wmem [3]int64 // sctp_wmem; in bytes
+
+	// Raw holds all the values as they are, keyed by the file names (e.g. `rto_min`).
+	Raw map[string]string
+}
+
+// sysctlSetters maps the file names of the SCTP tunables to the functions that set the value to a Sysctls.
+// Files that are not in this map are kept only in Sysctls.Raw.
+var sysctlSetters = map[string]func(s *Sysctls, v string) error{
+	"rto_initial":            durationSysctl(time.Millisecond, func(s *Sysctls) *time.Duration { return &s.RTOInitial }),
+	"rto_min":                durationSysctl(time.Millisecond, func(s *Sysctls) *time.Duration { return &s.RTOMin }),
+	"rto_max":                durationSysctl(time.Millisecond, func(s *Sysctls) *time.Duration { return &s.RTOMax }),
+	"rto_alpha_exp_divisor":  int64Sysctl(func(s *Sysctls) *int64 { return &s.RTOAlphaExpDivisor }),
+	"rto_beta_exp_divisor":   int64Sysctl(func(s *Sysctls) *int64 { return &s.RTOBetaExpDivisor }),
+	"hb_interval":            durationSysctl(time.Millisecond, func(s *Sysctls) *time.Duration { return &s.HBInterval }),
+	"sack_timeout":           durationSysctl(time.Millisecond, func(s *Sysctls) *time.Duration { return &s.SACKTimeout }),
+	"valid_cookie_life":      durationSysctl(time.Millisecond, func(s *Sysctls) *time.Duration { return &s.ValidCookieLife }),
+	"max_autoclose":          durationSysctl(time.Second, func(s *Sysctls) *time.Duration { return &s.MaxAutoclose }),
+	"plpmtud_probe_interval": durationSysctl(time.Millisecond, func(s *Sysctls) *time.Duration { return &s.ProbeInterval }),
+
+	"max_burst":               int64Sysctl(func(s *Sysctls) *int64 { return &s.MaxBurst }),
+	"association_max_retrans": int64Sysctl(func(s *Sysctls) *int64 { return &s.AssociationMaxRetrans }),
+	"path_max_retrans":        int64Sysctl(func(s *Sysctls) *int64 { return &s.PathMaxRetrans }),
+	"max_init_retransmits":    int64Sysctl(func(s *Sysctls) *int64 { return &s.MaxInitRetransmits }),
+	"pf_retrans":              int64Sysctl(func(s *Sysctls) *int64 { return &s.PFRetrans }),
+	"ps_retrans":              int64Sysctl(func(s *Sysctls) *int64 { return &s.PSRetrans }),
+	"pf_enable":               int64Sysctl(func(s *Sysctls) *int64 { return &s.PFEnable }),
+	"sndbuf_policy":           int64Sysctl(func(s *Sysctls) *int64 { return &s.SndbufPolicy }),
+	"rcvbuf_policy":           int64Sysctl(func(s *Sysctls) *int64 { return &s.RcvbufPolicy }),
+	"rwnd_update_shift":       int64Sysctl(func(s *Sysctls) *int64 { return &s.RwndUpdateShift }),
+	"udp_port":                int64Sysctl(func(s *Sysctls) *int64 { return &s.UDPPort }),
+	"encap_port":              int64Sysctl(func(s *Sysctls) *int64 { return &s.EncapPort }),
+
+	"addip_enable":           boolSysctl(func(s *Sysctls) *bool { return &s.AddipEnable }),
+	"addip_noauth_enable":    boolSysctl(func(s *Sysctls) *bool { return &s.AddipNoauthEnable }),
+	"auth_enable":            boolSysctl(func(s *Sysctls) *bool { return &s.AuthEnable }),
+	"prsctp_enable":          boolSysctl(func(s *Sysctls) *bool { return &s.PrsctpEnable }),
+	"reconf_enable":          boolSysctl(func(s *Sysctls) *bool { return &s.ReconfEnable }),
+	"intl_enable":            boolSysctl(func(s *Sysctls) *bool { return &s.IntlEnable }),
+	"ecn_enable":             boolSysctl(func(s *Sysctls) *bool { return &s.ECNEnable }),
+	"cookie_preserve_enable": boolSysctl(func(s *Sysctls) *bool { return &s.CookiePreserveEnable }),
+	"l3mdev_accept":          boolSysctl(func(s *Sysctls) *bool { return &s.L3mdevAccept }),
+
+	"cookie_hmac_alg": stringSysctl(func(s *Sysctls) *string { return &s.CookieHmacAlg }),
+
+	"sctp_mem":  tripleSysctl(func(s *Sysctls) *[3]int64 { return &s.Mem }),
+	"sctp_rmem": tripleSysctl(func(s *Sysctls) *[3]int64 { return &s.Rmem }),
+	"sctp_wmem": tripleSysctl(func(s *Sysctls) *[3]int64 { return &s.Wmem }),
+}
+
+// sysctlSetter returns the function that parses the value and sets it to the field of a Sysctls.
+func sysctlSetter[T any](parse func(v string) (T, error), field func(s *Sysctls) *T) func(s *Sysctls, v string) error {
+	return func(s *Sysctls, v string) error {
+		value, err := parse(v)
+		if err != nil {
+			return err
+		}
+		*field(s) = value
+		return nil
+	}
+}
+
+func int64Sysctl(field func(s *Sysctls) *int64) func(s *Sysctls, v string) error {
+	return sysctlSetter(func(v string) (int64, error) { return strconv.ParseInt(v, 10, 64) }, field)
+}
+
+func durationSysctl(unit time.Duration, field func(s *Sysctls) *time.Duration) func(s *Sysctls, v string) error {
+	return sysctlSetter(func(v string) (time.Duration, error) { return parseSysctlDuration(v, unit) }, field)
+}
+
+func boolSysctl(field func(s *Sysctls) *bool) func(s *Sysctls, v string) error {
+	return sysctlSetter(parseSysctlBool, field)
+}
+
+func stringSysctl(field func(s *Sysctls) *string) func(s *Sysctls, v string) error {
+	return sysctlSetter(func(v string) (string, error) { return v, nil }, field)
+}
+
+func tripleSysctl(field func(s *Sysctls) *[3]int64) func(s *Sysctls, v string) error {
+	return sysctlSetter(parseSysctlTriple, field)
+}
+
+// ParseSysctls parses the SCTP tunables.
+//
+// - values: the contents of the files under `/proc/sys/net/sctp` directory, keyed by the file names
+func ParseSysctls(values map[string]string) (*Sysctls, error) {
+	sysctls := &Sysctls{
+		Raw: make(map[string]string, len(values)),
+	}
+
+	for name, value := range values {
+		value = strings.TrimSpace(value)
+		sysctls.Raw[name] = value
+
+		set, ok := sysctlSetters[name]
+		if !ok {
+			continue
+		}
+		if err := set(sysctls, value); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, ErrInvalidSysctlFormat)
+		}
+	}
+
+	return sysctls, nil
+}
+
+func parseSysctlDuration(v string, unit time.Duration) (time.Duration, error) {
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(n) * unit, nil
+}
+
+func parseSysctlBool(v string) (bool, error) {
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return false, err
+	}
+	return n != 0, nil
+}
+
+func parseSysctlTriple(v string) ([3]int64, error) {
+	var triple [3]int64
+
+	leaves := spacesRe.Split(v, -1)
+	if len(leaves) != len(triple) {
+		return triple, ErrInvalidSysctlFormat
+	}
+	for i, leaf := range leaves {
+		n, err := strconv.ParseInt(leaf, 10, 64)
+		if err != nil {
+			return triple, err
+		}
+		triple[i] = n
+	}
+	return triple, nil
+}
@@ -0,0 +1,60 @@
+package parser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSysctls(t *testing.T) {
+	sysctls, err := ParseSysctls(map[string]string{
+		"rto_initial":             "3000\n",
+		"rto_min":                 "1000\n",
+		"rto_max":                 "60000\n",
+		"hb_interval":             "30000\n",
+		"max_autoclose":           "8589934\n",
+		"path_max_retrans":        "5\n",
+		"association_max_retrans": "10\n",
+		"pf_retrans":              "0\n",
+		"sndbuf_policy":           "0\n",
+		"addip_enable":            "1\n",
+		"auth_enable":             "0\n",
+		"cookie_hmac_alg":         "sha1\n",
+		"sctp_mem":                "90903\t121205\t181806\n",
+		"unknown_tunable":         "42\n",
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3*time.Second, sysctls.RTOInitial)
+	assert.Equal(t, time.Second, sysctls.RTOMin)
+	assert.Equal(t, time.Minute, sysctls.RTOMax)
+	assert.Equal(t, 30*time.Second, sysctls.HBInterval)
+	assert.Equal(t, 8589934*time.Second, sysctls.MaxAutoclose)
+	assert.EqualValues(t, 5, sysctls.PathMaxRetrans)
+	assert.EqualValues(t, 10, sysctls.AssociationMaxRetrans)
+	assert.EqualValues(t, 0, sysctls.PFRetrans)
+	assert.EqualValues(t, 0, sysctls.SndbufPolicy)
+	assert.True(t, sysctls.AddipEnable)
+	assert.False(t, sysctls.AuthEnable)
+	assert.Equal(t, "sha1", sysctls.CookieHmacAlg)
+	assert.Equal(t, [3]int64{90903, 121205, 181806}, sysctls.Mem)
+
+	assert.Equal(t, "42", sysctls.Raw["unknown_tunable"])
+	_, ok := sysctls.Raw["pf_enable"]
+	assert.False(t, ok)
+}
+
+func TestParseSysctls_WithInvalidValue(t *testing.T) {
+	_, err := ParseSysctls(map[string]string{
+		"rto_min": "1s\n",
+	})
+	assert.ErrorIs(t, err, ErrInvalidSysctlFormat)
+	assert.Contains(t, err.Error(), "rto_min")
+
+	_, err = ParseSysctls(map[string]string{
+		"sctp_wmem": "4096 16384\n",
+	})
+	assert.ErrorIs(t, err, ErrInvalidSysctlFormat)
+	assert.Contains(t, err.Error(), "sctp_wmem")
+}
@@ -0,0 +1,111 @@
+package parser
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultPasswdPath is the path of the passwd file on a usual host.
+const DefaultPasswdPath = "/etc/passwd"
+
+// UserResolver resolves UIDs to user names with a passwd file.
+// The passwd file is read on the first lookup and cached until Reload is called. UserResolver is safe for concurrent use.
+type UserResolver struct {
+	path string
+
+	mu    sync.Mutex
+	names map[uint64]string
+}
+
+// NewUserResolver returns a new UserResolver that reads the passwd file of the path; e.g. `/host/etc/passwd` in a container.
+func NewUserResolver(path string) *UserResolver {
+	return &UserResolver{path: path}
+}
+
+// Lookup returns the name of the user that has the UID. It returns false as the second value if there is no such user.
+func (r *UserResolver) Lookup(uid uint64) (string, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.names == nil {
+		names, err := readPasswd(r.path)
+		if err != nil {
+			return "", false, err
+		}
+		r.names = names
+	}
+
+	name, ok := r.names[uid]
+	return name, ok, nil
+}
+
+// Reload drops the cached contents of the passwd file; the file will be read again on the next lookup.
+func (r *UserResolver) Reload() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.names = nil
+}
+
+// AnnotateAssocs sets the names of the users to Assoc.UserName. The UIDs that have no user leave UserName empty.
+func (r *UserResolver) AnnotateAssocs(assocs []*Assoc) error {
+	for _, assoc := range assocs {
+		name, _, err := r.Lookup(assoc.Uid)
+		if err != nil {
+			return err
+		}
+		assoc.UserName = name
+	}
+	return nil
+}
+
+// AnnotateEPS sets the names of the users to EPS.UserName. The UIDs that have no user leave UserName empty.
+func (r *UserResolver) AnnotateEPS(eps []*EPS) error {
+	for _, e := range eps {
+		name, _, err := r.Lookup(e.Uid)
+		if err != nil {
+			return err
+		}
+		e.UserName = name
+	}
+	return nil
+}
+
+// readPasswd reads the passwd file and returns the user names keyed by the UIDs.
+// If there are multiple users that have the same UID, the first one wins as getpwuid(3) does.
+func readPasswd(path string) (map[uint64]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	names := make(map[uint64]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// name:password:UID:GID:GECOS:directory:shell
+		leaves := strings.Split(line, ":")
+		if len(leaves) < 3 {
+			continue
+		}
+		uid, err := strconv.ParseUint(leaves[2], 10, 64)
+		if err != nil {
+			continue
+		}
+		if _, exists := names[uid]; !exists {
+			names[uid] = leaves[0]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return names, nil
+}
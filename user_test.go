@@ -0,0 +1,62 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserResolver(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "passwd")
+	assert.NoError(t, os.WriteFile(path, []byte(`# comment
+root:x:0:0:root:/root:/bin/bash
+diameter:x:1000:1000::/home/diameter:/bin/sh
+alias:x:1000:1000::/home/alias:/bin/sh
+broken
+`), 0o644))
+
+	resolver := NewUserResolver(path)
+
+	name, ok, err := resolver.Lookup(0)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "root", name)
+
+	name, ok, err = resolver.Lookup(1000)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "diameter", name)
+
+	_, ok, err = resolver.Lookup(2000)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	assocs := []*Assoc{{Uid: 1000}, {Uid: 2000}}
+	assert.NoError(t, resolver.AnnotateAssocs(assocs))
+	assert.Equal(t, "diameter", assocs[0].UserName)
+	assert.Empty(t, assocs[1].UserName)
+
+	eps := []*EPS{{Uid: 0}}
+	assert.NoError(t, resolver.AnnotateEPS(eps))
+	assert.Equal(t, "root", eps[0].UserName)
+
+	// cached until reloaded
+	assert.NoError(t, os.WriteFile(path, []byte("sctp:x:2000:2000::/:/bin/sh\n"), 0o644))
+	_, ok, _ = resolver.Lookup(2000)
+	assert.False(t, ok)
+	resolver.Reload()
+	name, ok, err = resolver.Lookup(2000)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "sctp", name)
+}
+
+func TestUserResolver_WithMissingFile(t *testing.T) {
+	resolver := NewUserResolver(filepath.Join(t.TempDir(), "passwd"))
+
+	_, _, err := resolver.Lookup(0)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	assert.ErrorIs(t, resolver.AnnotateAssocs([]*Assoc{{Uid: 0}}), os.ErrNotExist)
+}
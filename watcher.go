@@ -0,0 +1,219 @@
+package parser
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// DefaultRetransmitBurstThreshold is the default of Watcher.RetransmitBurstThreshold.
+const DefaultRetransmitBurstThreshold = 10
+
+// EventType is the type of an Event.
+type EventType int
+
+const (
+	// EventAssocUp is emitted when an association gets established.
+	EventAssocUp EventType = iota + 1
+	// EventAssocDown is emitted when an established association leaves the established state or goes away.
+	EventAssocDown
+	// EventPathFailover is emitted when the traffic of an association moves to another path (see AssociationView.ActivePath);
+	// e.g. the primary path has left ACTIVE while another path is ACTIVE, or it has come back.
+	// Unlike the changes of the primary remote address, this is emitted whether or not the primary remote address is reconfigured.
+	EventPathFailover
+	// EventPathStateChange is emitted when the state of a path to a remote address changes.
+	EventPathStateChange
+	// EventRetransmitBurst is emitted when the number of the retransmitted chunks (RTXC) of an association
+	// increases by Watcher.RetransmitBurstThreshold or more within a polling interval.
+	EventRetransmitBurst
+)
+
+var eventTypeNames = map[EventType]string{
+	EventAssocUp:         "AssocUp",
+	EventAssocDown:       "AssocDown",
+	EventPathFailover:    "PathFailover",
+	EventPathStateChange: "PathStateChange",
+	EventRetransmitBurst: "RetransmitBurst",
+}
+
+// String returns the name of the event type, e.g. `AssocUp`.
+func (t EventType) String() string {
+	if name, ok := eventTypeNames[t]; ok {
+		return name
+	}
+	return "EventType(" + strconv.Itoa(int(t)) + ")"
+}
+
+// Event represents a change of an association that Watcher has detected.
+type Event struct {
+	Type EventType
+	// Time is when the change has been detected.
+	Time    time.Time
+	AssocID int64
+	// Association is the association in the newer snapshot; or in the older one for EventAssocDown when the association has gone away.
+	Association *AssociationView
+
+	// Addr is the remote address of the new active path for EventPathFailover, or the remote address of the path for EventPathStateChange.
+	Addr string
+	// PrevAddr is the remote address of the previous active path for EventPathFailover.
+	PrevAddr string
+	// PathState and PrevPathState are the new and the previous states of the path for EventPathStateChange.
+	PathState     TransportState
+	PrevPathState TransportState
+	// Retransmits is the increase of the retransmitted chunks for EventRetransmitBurst.
+	Retransmits int64
+}
+
+// Watcher polls the SCTP state and emits the events of the associations.
+type Watcher struct {
+	// Source is where the snapshots are read from; see SnapshotReader.
+	Source Source
+	// Interval is the polling interval.
+	Interval time.Duration
+	// MaxAttempts is the number of the attempts to read a consistent snapshot on each polling.
+	MaxAttempts int
+	// RetransmitBurstThreshold is the increase of RTXC within a polling interval that triggers EventRetransmitBurst.
+	RetransmitBurstThreshold int64
+	// Clock provides the ticks of the polling, and the time and the backoff of reading the snapshots.
+	Clock Clock
+}
+
+// NewWatcher returns a new Watcher that polls the source at the interval.
+func NewWatcher(source Source, interval time.Duration) *Watcher {
+	return &Watcher{
+		Source:                   source,
+		Interval:                 interval,
+		MaxAttempts:              DefaultSnapshotAttempts,
+		RetransmitBurstThreshold: DefaultRetransmitBurstThreshold,
+		Clock:                    SystemClock{},
+	}
+}
+
+// Run polls the SCTP state and sends the events to the channel until the context is done or reading a snapshot fails.
+// It returns the context's error or the error of reading.
+//
+// The first snapshot is the baseline; it doesn't produce any events.
+// Inconsistent snapshots (see Snapshot.Consistent) are skipped so as not to emit false events.
+func (w *Watcher) Run(ctx context.Context, events chan<- *Event) error {
+	reader := &SnapshotReader{
+		Source:      w.Source,
+		MaxAttempts: w.MaxAttempts,
+		Backoff:     DefaultSnapshotBackoff,
+		Clock:       w.Clock,
+	}
+
+	ticker := w.Clock.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	var prev *Snapshot
+	for {
+		snapshot, err := reader.Read(ctx)
+		if err != nil {
+			return err
+		}
+		if snapshot.Consistent {
+			prev = snapshot
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C():
+		}
+	}
+
+	for {
+		var now time.Time
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case now = <-ticker.C():
+		}
+
+		snapshot, err := reader.Read(ctx)
+		if err != nil {
+			return err
+		}
+		if !snapshot.Consistent {
+			continue
+		}
+
+		for _, event := range w.eventsOf(DiffSnapshots(prev, snapshot), prev, snapshot, now) {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case events <- event:
+			}
+		}
+		prev = snapshot
+	}
+}
+
+func (w *Watcher) eventsOf(diff *SnapshotDiff, older *Snapshot, newer *Snapshot, now time.Time) []*Event {
+	events := make([]*Event, 0)
+
+	for _, view := range diff.Closed {
+		if view.Assoc.St == AssocStateEstablished {
+			events = append(events, &Event{Type: EventAssocDown, Time: now, AssocID: view.Assoc.AssocId, Association: view})
+		}
+	}
+	for _, view := range diff.Established {
+		if view.Assoc.St == AssocStateEstablished {
+			events = append(events, &Event{Type: EventAssocUp, Time: now, AssocID: view.Assoc.AssocId, Association: view})
+		}
+	}
+	for _, change := range diff.StateChanges {
+		view, _ := newer.Lookup(change.AssocID)
+		if change.New == AssocStateEstablished {
+			events = append(events, &Event{Type: EventAssocUp, Time: now, AssocID: change.AssocID, Association: view})
+		} else if change.Old == AssocStateEstablished {
+			events = append(events, &Event{Type: EventAssocDown, Time: now, AssocID: change.AssocID, Association: view})
+		}
+	}
+	for _, view := range newer.Associations {
+		oldView, ok := older.Lookup(view.Assoc.AssocId)
+		if !ok || !isSameAssociation(oldView.Assoc, view.Assoc) {
+			continue
+		}
+		oldPath, newPath := oldView.ActivePath(), view.ActivePath()
+		if oldPath == nil || newPath == nil || oldPath.Addr == newPath.Addr {
+			continue
+		}
+		events = append(events, &Event{
+			Type:        EventPathFailover,
+			Time:        now,
+			AssocID:     view.Assoc.AssocId,
+			Association: view,
+			Addr:        newPath.Addr,
+			PrevAddr:    oldPath.Addr,
+		})
+	}
+	for _, change := range diff.PathStateChanges {
+		view, _ := newer.Lookup(change.AssocID)
+		events = append(events, &Event{
+			Type:          EventPathStateChange,
+			Time:          now,
+			AssocID:       change.AssocID,
+			Association:   view,
+			Addr:          change.Addr,
+			PathState:     change.New,
+			PrevPathState: change.Old,
+		})
+	}
+	for _, delta := range diff.CounterDeltas {
+		if w.RetransmitBurstThreshold <= 0 || delta.Rtxc < w.RetransmitBurstThreshold {
+			continue
+		}
+		view, _ := newer.Lookup(delta.AssocID)
+		events = append(events, &Event{
+			Type:        EventRetransmitBurst,
+			Time:        now,
+			AssocID:     delta.AssocID,
+			Association: view,
+			Retransmits: delta.Rtxc,
+		})
+	}
+
+	return events
+}
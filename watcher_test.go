@@ -0,0 +1,322 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	ticker *fakeTicker
+}
+
+func newFakeClock(now time.Time) *fakeClock {
+	return &fakeClock{now: now, ticker: &fakeTicker{c: make(chan time.Time)}}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) NewTicker(d time.Duration) Ticker {
+	return c.ticker
+}
+
+// tick advances the clock by the duration and delivers a tick to the watcher.
+func (c *fakeClock) tick(d time.Duration) time.Time {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	now := c.now
+	c.mu.Unlock()
+
+	c.ticker.c <- now
+	return now
+}
+
+type fakeTicker struct {
+	c chan time.Time
+}
+
+func (t *fakeTicker) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTicker) Stop() {}
+
+// fakeRecords are the records of a snapshot that fakeSource returns.
+type fakeRecords struct {
+	assocs   []*Assoc
+	remaddrs []*Remaddr
+}
+
+// fakeSource returns the records one by one; each call of Assocs advances to the next records.
+type fakeSource struct {
+	MemorySource
+	records []*fakeRecords
+	err     error
+}
+
+func (s *fakeSource) Assocs(ctx context.Context) ([]*Assoc, error) {
+	if len(s.records) == 0 {
+		if s.err != nil {
+			return nil, s.err
+		}
+		return nil, errors.New("no more records")
+	}
+	s.Set(s.records[0].assocs, nil, s.records[0].remaddrs, nil)
+	s.records = s.records[1:]
+	return s.MemorySource.Assocs(ctx)
+}
+
+func TestWatcher(t *testing.T) {
+	source := &fakeSource{records: []*fakeRecords{
+		{[]*Assoc{
+			{AssocId: 1, LPort: 3868, RPort: 50000, St: AssocStateCookieEchoed, RAddrs: []string{"10.0.0.1", "10.0.0.2"}, PrimaryRAddr: "10.0.0.1"},
+		}, []*Remaddr{
+			{AssocID: 1, Addr: "10.0.0.1", State: TransportStateActive},
+			{AssocID: 1, Addr: "10.0.0.2", State: TransportStateActive},
+		}},
+		{[]*Assoc{
+			{AssocId: 1, LPort: 3868, RPort: 50000, St: AssocStateEstablished, RAddrs: []string{"10.0.0.1", "10.0.0.2"}, PrimaryRAddr: "10.0.0.1"},
+		}, []*Remaddr{
+			{AssocID: 1, Addr: "10.0.0.1", State: TransportStateActive},
+			{AssocID: 1, Addr: "10.0.0.2", State: TransportStateActive},
+		}},
+		// inconsistent: this must be skipped
+		{[]*Assoc{
+			{AssocId: 1, LPort: 3868, RPort: 50000, St: AssocStateEstablished, RAddrs: []string{"10.0.0.1", "10.0.0.2"}, PrimaryRAddr: "10.0.0.1"},
+		}, nil},
+		// the primary path fails; the kernel keeps the primary remote address, and sends over the other path
+		{[]*Assoc{
+			{AssocId: 1, LPort: 3868, RPort: 50000, St: AssocStateEstablished, RAddrs: []string{"10.0.0.1", "10.0.0.2"}, PrimaryRAddr: "10.0.0.1", Rtxc: 12},
+		}, []*Remaddr{
+			{AssocID: 1, Addr: "10.0.0.1", State: TransportStateInactive},
+			{AssocID: 1, Addr: "10.0.0.2", State: TransportStateActive},
+		}},
+		{[]*Assoc{}, []*Remaddr{}},
+	}}
+
+	clock := newFakeClock(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))
+	watcher := NewWatcher(source, time.Second)
+	watcher.Clock = clock
+	watcher.MaxAttempts = 1 // not to retry the inconsistent records
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := make(chan *Event, 10)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- watcher.Run(ctx, events)
+	}()
+
+	now := clock.tick(time.Second)
+	event := <-events
+	assert.Equal(t, EventAssocUp, event.Type)
+	assert.Equal(t, now, event.Time)
+	assert.EqualValues(t, 1, event.AssocID)
+	assert.Equal(t, AssocStateEstablished, event.Association.Assoc.St)
+
+	clock.tick(time.Second) // inconsistent snapshot
+
+	now = clock.tick(time.Second)
+	event = <-events
+	assert.Equal(t, EventPathFailover, event.Type)
+	assert.Equal(t, now, event.Time)
+	assert.Equal(t, "10.0.0.1", event.PrevAddr)
+	assert.Equal(t, "10.0.0.2", event.Addr)
+
+	event = <-events
+	assert.Equal(t, EventPathStateChange, event.Type)
+	assert.Equal(t, "10.0.0.1", event.Addr)
+	assert.Equal(t, TransportStateActive, event.PrevPathState)
+	assert.Equal(t, TransportStateInactive, event.PathState)
+
+	event = <-events
+	assert.Equal(t, EventRetransmitBurst, event.Type)
+	assert.EqualValues(t, 12, event.Retransmits)
+
+	clock.tick(time.Second)
+	event = <-events
+	assert.Equal(t, EventAssocDown, event.Type)
+	assert.EqualValues(t, 1, event.AssocID)
+	assert.Equal(t, AssocStateEstablished, event.Association.Assoc.St)
+
+	cancel()
+	assert.ErrorIs(t, <-errCh, context.Canceled)
+	assert.Empty(t, events)
+}
+
+func TestWatcher_RetransmitBelowThreshold(t *testing.T) {
+	source := &fakeSource{records: []*fakeRecords{
+		{[]*Assoc{{AssocId: 1, St: AssocStateEstablished, Rtxc: 1}}, nil},
+		{[]*Assoc{{AssocId: 1, St: AssocStateEstablished, Rtxc: 5}}, nil},
+		{[]*Assoc{{AssocId: 1, St: AssocStateEstablished, Rtxc: 11}}, nil},
+	}}
+
+	clock := newFakeClock(time.Now())
+	watcher := NewWatcher(source, time.Second)
+	watcher.Clock = clock
+	watcher.RetransmitBurstThreshold = 5
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := make(chan *Event, 10)
+	go func() {
+		_ = watcher.Run(ctx, events)
+	}()
+
+	clock.tick(time.Second)
+	clock.tick(time.Second)
+
+	event := <-events
+	assert.Equal(t, EventRetransmitBurst, event.Type)
+	assert.EqualValues(t, 6, event.Retransmits)
+}
+
+func TestWatcher_ReadError(t *testing.T) {
+	readErr := errors.New("read error")
+	source := &fakeSource{
+		records: []*fakeRecords{{nil, nil}},
+		err:     readErr,
+	}
+
+	clock := newFakeClock(time.Now())
+	watcher := NewWatcher(source, time.Second)
+	watcher.Clock = clock
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- watcher.Run(context.Background(), make(chan *Event))
+	}()
+
+	clock.tick(time.Second)
+	assert.ErrorIs(t, <-errCh, readErr)
+}
+
+// syncedSource notifies every completion of reading a snapshot to synchronize the test with the watcher.
+// The endpoints are the last records that ReadSnapshotFrom reads.
+type syncedSource struct {
+	Source
+	read chan struct{}
+}
+
+func (s *syncedSource) Endpoints(ctx context.Context) ([]*EPS, error) {
+	defer func() { s.read <- struct{}{} }()
+	return s.Source.Endpoints(ctx)
+}
+
+func TestWatcher_WithProcFS(t *testing.T) {
+	root := t.TempDir()
+	writeProcFiles(t, root, map[string]string{
+		"net/sctp/assocs": ` ASSOC     SOCK   STY SST ST HBKT ASSOC-ID TX_QUEUE RX_QUEUE UID INODE LPORT RPORT LADDRS <-> RADDRS HBINT INS OUTS MAXRT T1X T2X RTXC wmema wmemq sndbuf rcvbuf
+`,
+		"net/sctp/remaddr": `ADDR ASSOC_ID HB_ACT RTO MAX_PATH_RTX REM_ADDR_RTX START STATE
+`,
+		"net/sctp/eps": ` ENDPT     SOCK   STY SST HBKT LPORT   UID INODE LADDRS
+`,
+	})
+
+	clock := newFakeClock(time.Now())
+	source := &syncedSource{Source: NewProcFS(root), read: make(chan struct{})}
+	watcher := NewWatcher(source, time.Second)
+	watcher.Clock = clock
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := make(chan *Event, 10)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- watcher.Run(ctx, events)
+	}()
+
+	<-source.read // baseline
+
+	writeProcFiles(t, root, map[string]string{
+		"net/sctp/assocs": ` ASSOC     SOCK   STY SST ST HBKT ASSOC-ID TX_QUEUE RX_QUEUE UID INODE LPORT RPORT LADDRS <-> RADDRS HBINT INS OUTS MAXRT T1X T2X RTXC wmema wmemq sndbuf rcvbuf
+       0        0 2   1   3  0      60        0      496       0 188897 12345 54321  127.0.0.1 <-> *127.0.0.2 	   30000 65535 65535   10    0    0        0        1        0   212992   212992
+`,
+		"net/sctp/remaddr": `ADDR ASSOC_ID HB_ACT RTO MAX_PATH_RTX REM_ADDR_RTX START STATE
+127.0.0.2  60 1 3000 5 0 0 2
+`,
+	})
+	clock.tick(time.Second)
+	<-source.read
+
+	event := <-events
+	assert.Equal(t, EventAssocUp, event.Type)
+	assert.EqualValues(t, 60, event.AssocID)
+
+	cancel()
+	assert.ErrorIs(t, <-errCh, context.Canceled)
+}
+
+func TestEventType_String(t *testing.T) {
+	assert.Equal(t, "AssocUp", EventAssocUp.String())
+	assert.Equal(t, "RetransmitBurst", EventRetransmitBurst.String())
+	assert.Equal(t, "EventType(99)", EventType(99).String())
+}
+
+func TestWatcher_WithProcFS_ClientAssociation(t *testing.T) {
+	root := t.TempDir()
+	// the association has been initiated by connect() without listen(), so eps doesn't list its socket
+	writeProcFiles(t, root, map[string]string{
+		"net/sctp/assocs": ` ASSOC     SOCK   STY SST ST HBKT ASSOC-ID TX_QUEUE RX_QUEUE UID INODE LPORT RPORT LADDRS <-> RADDRS HBINT INS OUTS MAXRT T1X T2X RTXC wmema wmemq sndbuf rcvbuf
+       0        0 2   1   3  0      60        0        0       0 188897 40000 3868  127.0.0.1 <-> *127.0.0.2 127.0.0.3 	   30000 10 10   10    0    0        0        1        0   212992   212992
+`,
+		"net/sctp/remaddr": `ADDR ASSOC_ID HB_ACT RTO MAX_PATH_RTX REM_ADDR_RTX START STATE
+127.0.0.2  60 1 3000 5 0 0 2
+127.0.0.3  60 1 3000 5 0 0 2
+`,
+		"net/sctp/eps": ` ENDPT     SOCK   STY SST HBKT LPORT   UID INODE LADDRS
+`,
+	})
+
+	clock := newFakeClock(time.Now())
+	source := &syncedSource{Source: NewProcFS(root), read: make(chan struct{})}
+	watcher := NewWatcher(source, time.Second)
+	watcher.Clock = clock
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := make(chan *Event, 10)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- watcher.Run(ctx, events)
+	}()
+
+	<-source.read // baseline; this has to be consistent at the first attempt
+
+	writeProcFiles(t, root, map[string]string{
+		"net/sctp/remaddr": `ADDR ASSOC_ID HB_ACT RTO MAX_PATH_RTX REM_ADDR_RTX START STATE
+127.0.0.2  60 1 3000 5 0 0 1
+127.0.0.3  60 1 3000 5 0 0 2
+`,
+	})
+	clock.tick(time.Second)
+	<-source.read
+
+	event := <-events
+	assert.Equal(t, EventPathFailover, event.Type)
+	assert.Equal(t, "127.0.0.2", event.PrevAddr)
+	assert.Equal(t, "127.0.0.3", event.Addr)
+	assert.Equal(t, "127.0.0.2", event.Association.Assoc.PrimaryRAddr)
+
+	event = <-events
+	assert.Equal(t, EventPathStateChange, event.Type)
+	assert.Equal(t, TransportStatePF, event.PathState)
+
+	cancel()
+	assert.ErrorIs(t, <-errCh, context.Canceled)
+}